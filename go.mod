@@ -2,4 +2,4 @@ module uttt
 
 go 1.20
 
-require google.golang.org/protobuf v1.30.0 // indirect
+require google.golang.org/protobuf v1.30.0
@@ -0,0 +1,73 @@
+package board
+
+import "fmt"
+
+// ========== Make/Unmake ==========
+
+// An UndoRecord holds everything needed to take back a move made with
+// Position.Apply
+type UndoRecord struct {
+	// the index of the move's space among all the spaces of the board
+	Space uint32
+	// who made the move
+	Owner Owner
+
+	// the board and the cells holding the space before the move,
+	// outermost first
+	prevNodes       [MAX_LEVELS]node
+	prevTargetLevel int
+	prevTarget      uint32
+	prevKey         uint64
+	prevStale       bool
+}
+
+// Apply makes the move for the player whose turn it is. The move's
+// space gets claimed, and the cells holding it are won if it wins them,
+// which can cascade up to the outermost cell. The next move has to be
+// made in the cell that lines up with the move's position: the move's
+// coordinates after the outermost one, cut off at the first cell that
+// the rules say isn't open. If even the outermost of those isn't open,
+// the next move is free.
+// The move should already have been checked with Legal; Apply panics
+// if it isn't even on the board, rather than claiming some other space
+func (p *Position) Apply(m *Move) UndoRecord {
+	idx, valid := p.size.MoveIndex(m)
+	if !valid {
+		panic(fmt.Sprintf("board: Apply of move %v, which isn't on a %s board", m, p.size.Tag()))
+	}
+	return p.apply(idx)
+}
+
+// applies the move to the space with the given index
+func (p *Position) apply(space uint32) UndoRecord {
+	rec := UndoRecord{Space: space, Owner: p.turn, prevTargetLevel: p.targetLevel, prevTarget: p.target, prevKey: p.key, prevStale: p.staleTarget}
+	n := uint32(p.g.n)
+	idx := space
+	for level := p.size.Levels - 1; level >= 0; level-- {
+		idx /= n
+		rec.prevNodes[level] = *p.node(level, idx)
+	}
+
+	p.key ^= zobristCur(p.targetLevel, p.target)
+	player, _ := playerIndex(p.turn)
+	p.play(space, player, p.rules)
+	p.key ^= zobristSpace(p.turn, space) ^ zobristCur(p.targetLevel, p.target) ^ zobristTurn
+	p.turn = p.turn.Opponent()
+	p.staleTarget = false
+	return rec
+}
+
+// Undo takes back a move made with Apply. Moves have to be undone in
+// the reverse order that they were applied
+func (p *Position) Undo(rec UndoRecord) {
+	n := uint32(p.g.n)
+	idx := rec.Space
+	for level := p.size.Levels - 1; level >= 0; level-- {
+		idx /= n
+		*p.node(level, idx) = rec.prevNodes[level]
+	}
+	p.targetLevel, p.target = rec.prevTargetLevel, rec.prevTarget
+	p.key = rec.prevKey
+	p.turn = rec.Owner
+	p.staleTarget = rec.prevStale
+}
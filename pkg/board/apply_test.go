@@ -0,0 +1,18 @@
+package board
+
+import "testing"
+
+// moves that aren't on the board panic instead of claiming another space
+func TestApplyInvalid(t *testing.T) {
+	p := NewPosition(DefaultSize(), StandardRules{})
+	m := NewMove(&Coord{Row: 4, Col: 0}, &Coord{Row: 0, Col: 0})
+	defer func() {
+		if recover() == nil {
+			t.Errorf("applied %v without a panic", m)
+		}
+		if p.Count(Owner_PLAYER1) != 0 || p.Key() != p.computeKey() {
+			t.Errorf("the position changed to %s", p.Notation())
+		}
+	}()
+	p.Apply(m)
+}
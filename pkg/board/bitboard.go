@@ -0,0 +1,404 @@
+package board
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// ========== geometry ==========
+
+// a geometry is everything precomputed for the shape of a single node
+type geometry struct {
+	// the number of children of a node
+	n int
+	// a mask with a bit set for every child of a node
+	full uint64
+	// the masks of every line that wins a node, in the order that
+	// decides the owner when both players have one:
+	// rows, columns, then the diagonals
+	lines []uint64
+	// whether or not a mask contains at least one of the lines.
+	// Only kept for nodes of up to maxTableCells children; bigger
+	// nodes check the lines directly
+	table []bool
+}
+
+// the most children a node can have for its geometry to keep a table
+const maxTableCells = 16
+
+var (
+	geometryMu sync.Mutex
+	geometries [MAX_SIDE + 1][MAX_SIDE + 1][MAX_SIDE + 1]atomic.Pointer[geometry]
+)
+
+// returns the geometry of a valid size, computing it the first time.
+// It doesn't depend on the number of levels
+func geometryOf(s Size) *geometry {
+	slot := &geometries[s.Rows][s.Cols][s.InARow]
+	if g := slot.Load(); g != nil {
+		return g
+	}
+
+	geometryMu.Lock()
+	defer geometryMu.Unlock()
+	if g := slot.Load(); g != nil {
+		return g
+	}
+	g := newGeometry(s)
+	slot.Store(g)
+	return g
+}
+
+func newGeometry(s Size) *geometry {
+	g := &geometry{n: s.Cells()}
+	g.full = 1<<g.n - 1
+
+	// every line of InARow children going in the direction (dRow, dCol)
+	addLines := func(dRow, dCol int) {
+		for row := 0; row < s.Rows; row++ {
+			for col := 0; col < s.Cols; col++ {
+				endRow, endCol := row+dRow*(s.InARow-1), col+dCol*(s.InARow-1)
+				if endRow < 0 || endRow >= s.Rows || endCol < 0 || endCol >= s.Cols {
+					continue
+				}
+				var line uint64
+				for i := 0; i < s.InARow; i++ {
+					line |= 1 << ((row+dRow*i)*s.Cols + col + dCol*i)
+				}
+				g.lines = append(g.lines, line)
+			}
+		}
+	}
+	addLines(0, 1)
+	addLines(1, 0)
+	addLines(1, 1)
+	addLines(1, -1)
+
+	if g.n <= maxTableCells {
+		g.table = make([]bool, 1<<g.n)
+		for mask := range g.table {
+			g.table[mask] = g.hasLine(uint64(mask))
+		}
+	}
+	return g
+}
+
+// whether or not the mask contains at least one line
+func (g *geometry) won(mask uint64) bool {
+	if g.table != nil {
+		return g.table[mask]
+	}
+	return g.hasLine(mask)
+}
+func (g *geometry) hasLine(mask uint64) bool {
+	for _, line := range g.lines {
+		if mask&line == line {
+			return true
+		}
+	}
+	return false
+}
+
+// who owns a node given the masks of the children owned by each player.
+// If both players have a line, the first line in lines decides
+func (g *geometry) lineOwner(p1, p2 uint64) Owner {
+	won1, won2 := g.won(p1), g.won(p2)
+	switch {
+	case !won1 && !won2:
+		return Owner_NONE
+	case !won2:
+		return Owner_PLAYER1
+	case !won1:
+		return Owner_PLAYER2
+	}
+	for _, line := range g.lines {
+		if p1&line == line {
+			return Owner_PLAYER1
+		}
+		if p2&line == line {
+			return Owner_PLAYER2
+		}
+	}
+	return Owner_NONE
+}
+
+// the children that would complete a line for the owner of the owned
+// children, given the children that are still available
+func (g *geometry) threats(owned, available uint64) uint64 {
+	var mask uint64
+	for _, line := range g.lines {
+		missing := line &^ owned
+		if missing&available != 0 && missing&(missing-1) == 0 {
+			mask |= missing
+		}
+	}
+	return mask
+}
+
+// ========== bitboard ==========
+// A bitboard holds a position as a handful of bit masks instead of
+// nested protobuf structs. The board and every cell are nodes whose
+// children are cells, or spaces for the deepest cells. Bit i of a
+// node's masks refers to its child with index i in row-major order,
+// so ownership and legality checks are just a few table lookups.
+
+type node struct {
+	// the children owned by each player; claimed spaces for the deepest
+	// cells, won cells otherwise. A cell belongs to whoever won it first
+	owned [2]uint64
+	// the children that can't be played in anymore under the rules
+	closed uint64
+}
+
+type bitboard struct {
+	g    *geometry
+	size Size
+
+	// every node level by level, starting with the board itself
+	nodes []node
+	// where each level starts in nodes
+	offsets [MAX_LEVELS + 1]uint32
+	// the number of nodes (or spaces) at each level
+	counts [MAX_LEVELS + 1]uint32
+
+	// the level and index of the cell the next move has to be made
+	// in; the board itself when moves may be made anywhere.
+	// It's always open
+	targetLevel int
+	target      uint32
+}
+
+// resets the bitboard to an empty board of the given valid size
+func (bb *bitboard) init(size Size) {
+	*bb = bitboard{g: geometryOf(size), size: size}
+	bb.counts[0] = 1
+	for level := 0; level < size.Levels; level++ {
+		bb.offsets[level+1] = bb.offsets[level] + bb.counts[level]
+		bb.counts[level+1] = bb.counts[level] * uint32(bb.g.n)
+	}
+	bb.nodes = make([]node, bb.offsets[size.Levels])
+}
+
+// loads a *Board into the bitboard, overwriting its contents.
+// The board should have the shape of its size, and the rules
+// decide which cells are closed
+func (bb *bitboard) load(b *Board, r Rules) {
+	size := b.Size()
+	bb.init(size)
+	bb.loadCells(b.Cells, 1, 0, r)
+
+	// the board's target can't be trusted to be open, so if the rules
+	// say it's closed, fall back to the cells holding it
+	bb.targetLevel, bb.target = size.target(b.CurCell, b.CurCells)
+	bb.openTarget()
+}
+
+// loads the cells held by the node at the level above
+func (bb *bitboard) loadCells(cells []*Cell, level int, parent uint32, r Rules) {
+	p := bb.node(level-1, parent)
+	for i, cell := range cells {
+		idx := parent*uint32(bb.g.n) + uint32(i)
+		if level == bb.size.Levels-1 {
+			nd := bb.node(level, idx)
+			for j, space := range cell.Spaces {
+				switch space.Val {
+				case Owner_PLAYER1:
+					nd.owned[0] |= 1 << j
+				case Owner_PLAYER2:
+					nd.owned[1] |= 1 << j
+				}
+			}
+			nd.closed = nd.owned[0] | nd.owned[1]
+		} else {
+			bb.loadCells(cell.Cells, level+1, idx, r)
+		}
+
+		switch cell.Winner {
+		case Owner_PLAYER1:
+			p.owned[0] |= 1 << i
+		case Owner_PLAYER2:
+			p.owned[1] |= 1 << i
+		}
+		bb.update(level, idx, r)
+	}
+}
+
+// the node with the given index at the given level
+func (bb *bitboard) node(level int, idx uint32) *node {
+	return &bb.nodes[bb.offsets[level]+idx]
+}
+
+// records who won the given cell if it was just won, and recomputes
+// whether or not it's closed
+func (bb *bitboard) update(level int, idx uint32, r Rules) {
+	n := uint32(bb.g.n)
+	nd, parent := bb.node(level, idx), bb.node(level-1, idx/n)
+	bit := uint64(1) << (idx % n)
+	if (parent.owned[0]|parent.owned[1])&bit == 0 {
+		switch bb.g.lineOwner(nd.owned[0], nd.owned[1]) {
+		case Owner_PLAYER1:
+			parent.owned[0] |= bit
+		case Owner_PLAYER2:
+			parent.owned[1] |= bit
+		}
+	}
+
+	parent.closed &^= bit
+	if !r.CellOpen(bb.childOwner(level, idx), nd.closed == bb.g.full) {
+		parent.closed |= bit
+	}
+}
+
+// the children of the node that nobody owns and that are still open
+func (bb *bitboard) available(nd node) uint64 {
+	return bb.g.full &^ (nd.owned[0] | nd.owned[1] | nd.closed)
+}
+
+// who owns the given cell, or space on the deepest level
+func (bb *bitboard) childOwner(level int, idx uint32) Owner {
+	n := uint32(bb.g.n)
+	parent, bit := bb.node(level-1, idx/n), uint64(1)<<(idx%n)
+	switch {
+	case parent.owned[0]&bit != 0:
+		return Owner_PLAYER1
+	case parent.owned[1]&bit != 0:
+		return Owner_PLAYER2
+	}
+	return Owner_NONE
+}
+
+// who owns the given outermost cell
+func (bb *bitboard) cellOwner(cell int) Owner {
+	return bb.childOwner(1, uint32(cell))
+}
+
+// the number of outermost cells owned by the player with the given index
+func (bb *bitboard) cellCount(player int) int {
+	return bits.OnesCount64(bb.nodes[0].owned[player])
+}
+
+// the number of spaces claimed by the player with the given index
+func (bb *bitboard) count(player int) int {
+	n := 0
+	for _, nd := range bb.nodes[bb.offsets[bb.size.Levels-1]:] {
+		n += bits.OnesCount64(nd.owned[player])
+	}
+	return n
+}
+
+// who owns the board
+func (bb *bitboard) owner() Owner {
+	return bb.g.lineOwner(bb.nodes[0].owned[0], bb.nodes[0].owned[1])
+}
+
+// whether or not no cell can be played in anymore
+func (bb *bitboard) allClosed() bool {
+	return bb.nodes[0].closed == bb.g.full
+}
+
+// whether or not the given cell (or space) and every cell holding it
+// are still open
+func (bb *bitboard) open(level int, idx uint32) bool {
+	n := uint32(bb.g.n)
+	for ; level > 0; level-- {
+		if bb.node(level-1, idx/n).closed&(1<<(idx%n)) != 0 {
+			return false
+		}
+		idx /= n
+	}
+	return true
+}
+
+// whether or not the given cell (or space) itself is closed, whether
+// or not the cells holding it are
+func (bb *bitboard) closed(level int, idx uint32) bool {
+	n := uint32(bb.g.n)
+	return bb.node(level-1, idx/n).closed&(1<<(idx%n)) != 0
+}
+
+// moves the target out to the cells holding it until it's open
+func (bb *bitboard) openTarget() {
+	for bb.targetLevel > 0 && !bb.open(bb.targetLevel, bb.target) {
+		bb.targetLevel--
+		bb.target /= uint32(bb.g.n)
+	}
+}
+
+// whether or not a move to the space with the given index is legal,
+// not counting whether or not the game is over
+func (bb *bitboard) legal(space uint32) bool {
+	levels := bb.size.Levels
+	if space >= bb.counts[levels] {
+		return false
+	}
+	if space/bb.counts[levels-bb.targetLevel] != bb.target {
+		return false
+	}
+	return bb.open(levels, space)
+}
+
+// appends the index of every space a legal move can be made in
+// to dst and returns the extended slice
+func (bb *bitboard) moves(dst []uint32) []uint32 {
+	return bb.appendOpen(dst, bb.targetLevel, bb.target)
+}
+
+// appends every open space inside the given node
+func (bb *bitboard) appendOpen(dst []uint32, level int, idx uint32) []uint32 {
+	free := bb.g.full &^ bb.node(level, idx).closed
+	for free != 0 {
+		child := idx*uint32(bb.g.n) + uint32(bits.TrailingZeros64(free))
+		free &= free - 1
+		if level == bb.size.Levels-1 {
+			dst = append(dst, child)
+		} else {
+			dst = bb.appendOpen(dst, level+1, child)
+		}
+	}
+	return dst
+}
+
+// claims the space with the given index for the player with the given
+// index, updates every cell holding it and points the target at the
+// cell the next move has to be made in
+func (bb *bitboard) play(space uint32, player int, r Rules) {
+	n := uint32(bb.g.n)
+	levels := bb.size.Levels
+	nd, bit := bb.node(levels-1, space/n), uint64(1)<<(space%n)
+	nd.owned[player] |= bit
+	nd.closed |= bit
+	for level, idx := levels-1, space/n; level > 0; level, idx = level-1, idx/n {
+		bb.update(level, idx, r)
+	}
+
+	bb.targetLevel, bb.target = bb.sentTo(space)
+}
+
+// the level and index of the cell a move to the space sends the next
+// move to as things stand: the one lining up with the space's position
+// below the outermost cell, or as much of it as is open
+func (bb *bitboard) sentTo(space uint32) (targetLevel int, target uint32) {
+	levels := bb.size.Levels
+	next := space % bb.counts[levels-1]
+	for level := 1; level < levels; level++ {
+		idx := next / bb.counts[levels-1-level]
+		if !bb.open(level, idx) {
+			break
+		}
+		targetLevel, target = level, idx
+	}
+	return
+}
+
+// calls fn with the index and owner of every claimed space
+func (bb *bitboard) eachSpace(fn func(space uint32, owner Owner)) {
+	deepest := bb.size.Levels - 1
+	for i, nd := range bb.nodes[bb.offsets[deepest]:] {
+		for player, owner := range [2]Owner{Owner_PLAYER1, Owner_PLAYER2} {
+			for mask := nd.owned[player]; mask != 0; mask &= mask - 1 {
+				fn(uint32(i)*uint32(bb.g.n)+uint32(bits.TrailingZeros64(mask)), owner)
+			}
+		}
+	}
+}
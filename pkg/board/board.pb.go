@@ -71,6 +71,136 @@ func (Owner) EnumDescriptor() ([]byte, []int) {
 	return file_board_proto_rawDescGZIP(), []int{0}
 }
 
+// how a game ended; ONGOING if it hasn't yet.
+// RESIGNATION, TIMEOUT and FORFEIT are losses for the player
+// that resigned, ran out of time or made too many invalid moves
+type Result int32
+
+const (
+	Result_ONGOING     Result = 0
+	Result_PLAYER1_WIN Result = 1
+	Result_PLAYER2_WIN Result = 2
+	Result_DRAW        Result = 3
+	Result_RESIGNATION Result = 4
+	Result_TIMEOUT     Result = 5
+	Result_FORFEIT     Result = 6
+	Result_ABANDONED   Result = 7
+)
+
+// Enum value maps for Result.
+var (
+	Result_name = map[int32]string{
+		0: "ONGOING",
+		1: "PLAYER1_WIN",
+		2: "PLAYER2_WIN",
+		3: "DRAW",
+		4: "RESIGNATION",
+		5: "TIMEOUT",
+		6: "FORFEIT",
+		7: "ABANDONED",
+	}
+	Result_value = map[string]int32{
+		"ONGOING":     0,
+		"PLAYER1_WIN": 1,
+		"PLAYER2_WIN": 2,
+		"DRAW":        3,
+		"RESIGNATION": 4,
+		"TIMEOUT":     5,
+		"FORFEIT":     6,
+		"ABANDONED":   7,
+	}
+)
+
+func (x Result) Enum() *Result {
+	p := new(Result)
+	*p = x
+	return p
+}
+
+func (x Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_board_proto_enumTypes[1].Descriptor()
+}
+
+func (Result) Type() protoreflect.EnumType {
+	return &file_board_proto_enumTypes[1]
+}
+
+func (x Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Result.Descriptor instead.
+func (Result) EnumDescriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{1}
+}
+
+// why a move was rejected; ACCEPTED if it wasn't
+type Reason int32
+
+const (
+	Reason_ACCEPTED     Reason = 0
+	Reason_WRONG_CELL   Reason = 1
+	Reason_SPACE_TAKEN  Reason = 2
+	Reason_CELL_CLOSED  Reason = 3
+	Reason_OUT_OF_RANGE Reason = 4
+	Reason_GAME_OVER    Reason = 5
+	// not sent; the runner only asks the player whose turn it is for moves
+	Reason_NOT_YOUR_TURN Reason = 6
+)
+
+// Enum value maps for Reason.
+var (
+	Reason_name = map[int32]string{
+		0: "ACCEPTED",
+		1: "WRONG_CELL",
+		2: "SPACE_TAKEN",
+		3: "CELL_CLOSED",
+		4: "OUT_OF_RANGE",
+		5: "GAME_OVER",
+		6: "NOT_YOUR_TURN",
+	}
+	Reason_value = map[string]int32{
+		"ACCEPTED":      0,
+		"WRONG_CELL":    1,
+		"SPACE_TAKEN":   2,
+		"CELL_CLOSED":   3,
+		"OUT_OF_RANGE":  4,
+		"GAME_OVER":     5,
+		"NOT_YOUR_TURN": 6,
+	}
+)
+
+func (x Reason) Enum() *Reason {
+	p := new(Reason)
+	*p = x
+	return p
+}
+
+func (x Reason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Reason) Descriptor() protoreflect.EnumDescriptor {
+	return file_board_proto_enumTypes[2].Descriptor()
+}
+
+func (Reason) Type() protoreflect.EnumType {
+	return &file_board_proto_enumTypes[2]
+}
+
+func (x Reason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Reason.Descriptor instead.
+func (Reason) EnumDescriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{2}
+}
+
 // a single board coordinate;
 // negative values = invalid
 type Coord struct {
@@ -128,14 +258,17 @@ func (x *Coord) GetCol() int32 {
 	return 0
 }
 
-// a move has a large (outer) coordinate and a small (inner) coordinate
+// a move has a large (outer) coordinate and a small (inner) coordinate.
+// on boards with more than two levels, mid holds the coordinates of
+// the cells in between, outermost first
 type Move struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Large *Coord `protobuf:"bytes,1,opt,name=large,proto3" json:"large,omitempty"`
-	Small *Coord `protobuf:"bytes,2,opt,name=small,proto3" json:"small,omitempty"`
+	Large *Coord   `protobuf:"bytes,1,opt,name=large,proto3" json:"large,omitempty"`
+	Small *Coord   `protobuf:"bytes,2,opt,name=small,proto3" json:"small,omitempty"`
+	Mid   []*Coord `protobuf:"bytes,3,rep,name=mid,proto3" json:"mid,omitempty"`
 }
 
 func (x *Move) Reset() {
@@ -184,6 +317,13 @@ func (x *Move) GetSmall() *Coord {
 	return nil
 }
 
+func (x *Move) GetMid() []*Coord {
+	if x != nil {
+		return x.Mid
+	}
+	return nil
+}
+
 // a space in a cell.
 type Space struct {
 	state         protoimpl.MessageState
@@ -232,13 +372,19 @@ func (x *Space) GetVal() Owner {
 	return Owner_NONE
 }
 
-// a cell is a section of the gameboard, containing spaces
+// a cell is a section of the gameboard, containing spaces, or
+// smaller cells on boards with more than two levels.
+// winner is whoever won the cell first; it's what decides the owner
+// under rules where won cells stay playable, since both players
+// can end up with a line in them
 type Cell struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Spaces []*Space `protobuf:"bytes,1,rep,name=spaces,proto3" json:"spaces,omitempty"`
+	Winner Owner    `protobuf:"varint,2,opt,name=winner,proto3,enum=uttt.Owner" json:"winner,omitempty"`
+	Cells  []*Cell  `protobuf:"bytes,3,rep,name=cells,proto3" json:"cells,omitempty"`
 }
 
 func (x *Cell) Reset() {
@@ -280,16 +426,39 @@ func (x *Cell) GetSpaces() []*Space {
 	return nil
 }
 
-// a Board is the entire gameboard, containing cells
+func (x *Cell) GetWinner() Owner {
+	if x != nil {
+		return x.Winner
+	}
+	return Owner_NONE
+}
+
+func (x *Cell) GetCells() []*Cell {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+// a Board is the entire gameboard, containing cells.
+// every cell has rows x cols spaces and the board has rows x cols
+// cells (0 means 3), and inarow marks in a line win a cell, or
+// cells in a line win the board (0 means the smaller of rows and cols).
+// levels is how deep the cells nest (0 means 2, cells of spaces);
+// on deeper boards curCells holds the cells inside curCell that the
+// next move has to be made in, outermost first
 type Board struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cells   []*Cell `protobuf:"bytes,1,rep,name=cells,proto3" json:"cells,omitempty"`
-	CurCell *Coord  `protobuf:"bytes,2,opt,name=curCell,proto3" json:"curCell,omitempty"`
-	Rows    int32   `protobuf:"varint,3,opt,name=rows,proto3" json:"rows,omitempty"`
-	Cols    int32   `protobuf:"varint,4,opt,name=cols,proto3" json:"cols,omitempty"`
+	Cells    []*Cell  `protobuf:"bytes,1,rep,name=cells,proto3" json:"cells,omitempty"`
+	CurCell  *Coord   `protobuf:"bytes,2,opt,name=curCell,proto3" json:"curCell,omitempty"`
+	Rows     int32    `protobuf:"varint,3,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols     int32    `protobuf:"varint,4,opt,name=cols,proto3" json:"cols,omitempty"`
+	Inarow   int32    `protobuf:"varint,5,opt,name=inarow,proto3" json:"inarow,omitempty"`
+	Levels   int32    `protobuf:"varint,6,opt,name=levels,proto3" json:"levels,omitempty"`
+	CurCells []*Coord `protobuf:"bytes,7,rep,name=curCells,proto3" json:"curCells,omitempty"`
 }
 
 func (x *Board) Reset() {
@@ -352,10 +521,32 @@ func (x *Board) GetCols() int32 {
 	return 0
 }
 
+func (x *Board) GetInarow() int32 {
+	if x != nil {
+		return x.Inarow
+	}
+	return 0
+}
+
+func (x *Board) GetLevels() int32 {
+	if x != nil {
+		return x.Levels
+	}
+	return 0
+}
+
+func (x *Board) GetCurCells() []*Coord {
+	if x != nil {
+		return x.CurCells
+	}
+	return nil
+}
+
 // contains info about the current state of the game
 // specificially; it contains the board, the owners of the cells,
-// the current turn, the winner (if any), and whether or not
-// the game is done
+// the current turn, the winner (if any), whether or not
+// the game is done, how it ended, and the name of the rules
+// being played
 type StateMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -367,6 +558,8 @@ type StateMessage struct {
 	Winner     Owner   `protobuf:"varint,4,opt,name=winner,proto3,enum=uttt.Owner" json:"winner,omitempty"`
 	Done       bool    `protobuf:"varint,5,opt,name=done,proto3" json:"done,omitempty"`
 	Validmoves []*Move `protobuf:"bytes,6,rep,name=validmoves,proto3" json:"validmoves,omitempty"`
+	Result     Result  `protobuf:"varint,7,opt,name=result,proto3,enum=uttt.Result" json:"result,omitempty"`
+	Rules      string  `protobuf:"bytes,8,opt,name=rules,proto3" json:"rules,omitempty"`
 }
 
 func (x *StateMessage) Reset() {
@@ -443,6 +636,20 @@ func (x *StateMessage) GetValidmoves() []*Move {
 	return nil
 }
 
+func (x *StateMessage) GetResult() Result {
+	if x != nil {
+		return x.Result
+	}
+	return Result_ONGOING
+}
+
+func (x *StateMessage) GetRules() string {
+	if x != nil {
+		return x.Rules
+	}
+	return ""
+}
+
 // contains info about the action that will be taken
 // Specifically, it contains a move
 type ActionMessage struct {
@@ -492,22 +699,248 @@ func (x *ActionMessage) GetMove() *Move {
 	return nil
 }
 
+// the coordinates of a cell, outermost first
+type Path struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Coords []*Coord `protobuf:"bytes,1,rep,name=coords,proto3" json:"coords,omitempty"`
+}
+
+func (x *Path) Reset() {
+	*x = Path{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_board_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Path) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Path) ProtoMessage() {}
+
+func (x *Path) ProtoReflect() protoreflect.Message {
+	mi := &file_board_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Path.ProtoReflect.Descriptor instead.
+func (*Path) Descriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Path) GetCoords() []*Coord {
+	if x != nil {
+		return x.Coords
+	}
+	return nil
+}
+
+// a line that player can complete by winning one more cell
+// (or claiming one more space) inside cell; at is its coordinate.
+// An empty cell means the board itself
+type ThreatMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Player Owner  `protobuf:"varint,1,opt,name=player,proto3,enum=uttt.Owner" json:"player,omitempty"`
+	Cell   *Path  `protobuf:"bytes,2,opt,name=cell,proto3" json:"cell,omitempty"`
+	At     *Coord `protobuf:"bytes,3,opt,name=at,proto3" json:"at,omitempty"`
+}
+
+func (x *ThreatMessage) Reset() {
+	*x = ThreatMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_board_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThreatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreatMessage) ProtoMessage() {}
+
+func (x *ThreatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_board_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreatMessage.ProtoReflect.Descriptor instead.
+func (*ThreatMessage) Descriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ThreatMessage) GetPlayer() Owner {
+	if x != nil {
+		return x.Player
+	}
+	return Owner_NONE
+}
+
+func (x *ThreatMessage) GetCell() *Path {
+	if x != nil {
+		return x.Cell
+	}
+	return nil
+}
+
+func (x *ThreatMessage) GetAt() *Coord {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+// what a move changed
+type MoveResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Player Owner `protobuf:"varint,1,opt,name=player,proto3,enum=uttt.Owner" json:"player,omitempty"`
+	// cells the move won, innermost first
+	Captured []*Path `protobuf:"bytes,2,rep,name=captured,proto3" json:"captured,omitempty"`
+	// cells that can't be won by anyone anymore because of the move
+	Drawn  []*Path `protobuf:"bytes,3,rep,name=drawn,proto3" json:"drawn,omitempty"`
+	Result Result  `protobuf:"varint,4,opt,name=result,proto3,enum=uttt.Result" json:"result,omitempty"`
+	Winner Owner   `protobuf:"varint,5,opt,name=winner,proto3,enum=uttt.Owner" json:"winner,omitempty"`
+	// whether the next move can be made in any cell; if not, next is
+	// the cell it has to be made in. Neither is set once the game is over
+	Free bool     `protobuf:"varint,6,opt,name=free,proto3" json:"free,omitempty"`
+	Next []*Coord `protobuf:"bytes,7,rep,name=next,proto3" json:"next,omitempty"`
+	// lines the move set up
+	Threats []*ThreatMessage `protobuf:"bytes,8,rep,name=threats,proto3" json:"threats,omitempty"`
+}
+
+func (x *MoveResultMessage) Reset() {
+	*x = MoveResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_board_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveResultMessage) ProtoMessage() {}
+
+func (x *MoveResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_board_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveResultMessage.ProtoReflect.Descriptor instead.
+func (*MoveResultMessage) Descriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MoveResultMessage) GetPlayer() Owner {
+	if x != nil {
+		return x.Player
+	}
+	return Owner_NONE
+}
+
+func (x *MoveResultMessage) GetCaptured() []*Path {
+	if x != nil {
+		return x.Captured
+	}
+	return nil
+}
+
+func (x *MoveResultMessage) GetDrawn() []*Path {
+	if x != nil {
+		return x.Drawn
+	}
+	return nil
+}
+
+func (x *MoveResultMessage) GetResult() Result {
+	if x != nil {
+		return x.Result
+	}
+	return Result_ONGOING
+}
+
+func (x *MoveResultMessage) GetWinner() Owner {
+	if x != nil {
+		return x.Winner
+	}
+	return Owner_NONE
+}
+
+func (x *MoveResultMessage) GetFree() bool {
+	if x != nil {
+		return x.Free
+	}
+	return false
+}
+
+func (x *MoveResultMessage) GetNext() []*Coord {
+	if x != nil {
+		return x.Next
+	}
+	return nil
+}
+
+func (x *MoveResultMessage) GetThreats() []*ThreatMessage {
+	if x != nil {
+		return x.Threats
+	}
+	return nil
+}
+
 // this should be sent after an action is taken
 // it returns another state message as well as whether
-// or not the move was valid
+// or not the move was valid, and why not if it wasn't.
+// If it was, result says what it changed
 type ReturnMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	State *StateMessage `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
-	Valid bool          `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	State  *StateMessage      `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Valid  bool               `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	Reason Reason             `protobuf:"varint,3,opt,name=reason,proto3,enum=uttt.Reason" json:"reason,omitempty"`
+	Result *MoveResultMessage `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
 }
 
 func (x *ReturnMessage) Reset() {
 	*x = ReturnMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_board_proto_msgTypes[7]
+		mi := &file_board_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -520,7 +953,7 @@ func (x *ReturnMessage) String() string {
 func (*ReturnMessage) ProtoMessage() {}
 
 func (x *ReturnMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_board_proto_msgTypes[7]
+	mi := &file_board_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -533,7 +966,7 @@ func (x *ReturnMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReturnMessage.ProtoReflect.Descriptor instead.
 func (*ReturnMessage) Descriptor() ([]byte, []int) {
-	return file_board_proto_rawDescGZIP(), []int{7}
+	return file_board_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ReturnMessage) GetState() *StateMessage {
@@ -550,34 +983,440 @@ func (x *ReturnMessage) GetValid() bool {
 	return false
 }
 
-var File_board_proto protoreflect.FileDescriptor
+func (x *ReturnMessage) GetReason() Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Reason_ACCEPTED
+}
 
-var file_board_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x75,
-	0x74, 0x74, 0x74, 0x22, 0x2b, 0x0a, 0x05, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x12, 0x10, 0x0a, 0x03,
-	0x72, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x72, 0x6f, 0x77, 0x12, 0x10,
-	0x0a, 0x03, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c,
-	0x22, 0x4c, 0x0a, 0x04, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x6c, 0x61, 0x72, 0x67,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43,
-	0x6f, 0x6f, 0x72, 0x64, 0x52, 0x05, 0x6c, 0x61, 0x72, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73,
-	0x6d, 0x61, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74,
-	0x74, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x52, 0x05, 0x73, 0x6d, 0x61, 0x6c, 0x6c, 0x22, 0x26,
-	0x0a, 0x05, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x03, 0x76, 0x61, 0x6c, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e, 0x65,
-	0x72, 0x52, 0x03, 0x76, 0x61, 0x6c, 0x22, 0x2b, 0x0a, 0x04, 0x43, 0x65, 0x6c, 0x6c, 0x12, 0x23,
-	0x0a, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b,
-	0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x06, 0x73, 0x70, 0x61,
-	0x63, 0x65, 0x73, 0x22, 0x78, 0x0a, 0x05, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x20, 0x0a, 0x05,
-	0x63, 0x65, 0x6c, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x74,
-	0x74, 0x74, 0x2e, 0x43, 0x65, 0x6c, 0x6c, 0x52, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x12, 0x25,
-	0x0a, 0x07, 0x63, 0x75, 0x72, 0x43, 0x65, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x52, 0x07, 0x63, 0x75,
-	0x72, 0x43, 0x65, 0x6c, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x6c,
-	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x6c, 0x73, 0x22, 0xe4, 0x01,
-	0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x21,
-	0x0a, 0x05, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
-	0x75, 0x74, 0x74, 0x74, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x05, 0x62, 0x6f, 0x61, 0x72,
+func (x *ReturnMessage) GetResult() *MoveResultMessage {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// who played a side of a game. name is what the player is called,
+// e.g. "human" or "ai", and version tells apart versions of the same
+// program or model
+type PlayerInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *PlayerInfo) Reset() {
+	*x = PlayerInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_board_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerInfo) ProtoMessage() {}
+
+func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_board_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerInfo.ProtoReflect.Descriptor instead.
+func (*PlayerInfo) Descriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PlayerInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// an engine's evaluation of the position a move was made in, from the
+// point of view of the player making it. Engines pick their own scale;
+// depth is how far ahead they looked (0 if that doesn't apply)
+type Evaluation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Engine string  `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	Score  float32 `protobuf:"fixed32,2,opt,name=score,proto3" json:"score,omitempty"`
+	Depth  int32   `protobuf:"varint,3,opt,name=depth,proto3" json:"depth,omitempty"`
+}
+
+func (x *Evaluation) Reset() {
+	*x = Evaluation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_board_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Evaluation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Evaluation) ProtoMessage() {}
+
+func (x *Evaluation) ProtoReflect() protoreflect.Message {
+	mi := &file_board_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Evaluation.ProtoReflect.Descriptor instead.
+func (*Evaluation) Descriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Evaluation) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *Evaluation) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Evaluation) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+// a move of a recorded game. time is when it was made and thinkTime
+// how long it took, both in milliseconds (0 if unknown); time counts
+// from the Unix epoch. evaluation is only set if the position was
+// evaluated, and comment follows the move
+type MoveRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Move       *Move       `protobuf:"bytes,1,opt,name=move,proto3" json:"move,omitempty"`
+	Player     Owner       `protobuf:"varint,2,opt,name=player,proto3,enum=uttt.Owner" json:"player,omitempty"`
+	Time       int64       `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	ThinkTime  int64       `protobuf:"varint,4,opt,name=thinkTime,proto3" json:"thinkTime,omitempty"`
+	Evaluation *Evaluation `protobuf:"bytes,5,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	Comment    string      `protobuf:"bytes,6,opt,name=comment,proto3" json:"comment,omitempty"`
+}
+
+func (x *MoveRecord) Reset() {
+	*x = MoveRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_board_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRecord) ProtoMessage() {}
+
+func (x *MoveRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_board_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRecord.ProtoReflect.Descriptor instead.
+func (*MoveRecord) Descriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MoveRecord) GetMove() *Move {
+	if x != nil {
+		return x.Move
+	}
+	return nil
+}
+
+func (x *MoveRecord) GetPlayer() Owner {
+	if x != nil {
+		return x.Player
+	}
+	return Owner_NONE
+}
+
+func (x *MoveRecord) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *MoveRecord) GetThinkTime() int64 {
+	if x != nil {
+		return x.ThinkTime
+	}
+	return 0
+}
+
+func (x *MoveRecord) GetEvaluation() *Evaluation {
+	if x != nil {
+		return x.Evaluation
+	}
+	return nil
+}
+
+func (x *MoveRecord) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+// a played game. The size works like a Board's (0 means the default),
+// opening is "free", "center" or the index of a cell, and position is
+// the notation of the position the game started from if it wasn't an
+// empty board following the opening. started is when the game started
+// and timeControl how long each move could take, both in milliseconds
+// (0 if unknown or unlimited). comment comes before the first move
+type GameRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Player1     *PlayerInfo   `protobuf:"bytes,1,opt,name=player1,proto3" json:"player1,omitempty"`
+	Player2     *PlayerInfo   `protobuf:"bytes,2,opt,name=player2,proto3" json:"player2,omitempty"`
+	Started     int64         `protobuf:"varint,3,opt,name=started,proto3" json:"started,omitempty"`
+	Rules       string        `protobuf:"bytes,4,opt,name=rules,proto3" json:"rules,omitempty"`
+	Rows        int32         `protobuf:"varint,5,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols        int32         `protobuf:"varint,6,opt,name=cols,proto3" json:"cols,omitempty"`
+	Inarow      int32         `protobuf:"varint,7,opt,name=inarow,proto3" json:"inarow,omitempty"`
+	Levels      int32         `protobuf:"varint,8,opt,name=levels,proto3" json:"levels,omitempty"`
+	Opening     string        `protobuf:"bytes,9,opt,name=opening,proto3" json:"opening,omitempty"`
+	Position    string        `protobuf:"bytes,10,opt,name=position,proto3" json:"position,omitempty"`
+	TimeControl int64         `protobuf:"varint,11,opt,name=timeControl,proto3" json:"timeControl,omitempty"`
+	Moves       []*MoveRecord `protobuf:"bytes,12,rep,name=moves,proto3" json:"moves,omitempty"`
+	Result      Result        `protobuf:"varint,13,opt,name=result,proto3,enum=uttt.Result" json:"result,omitempty"`
+	Winner      Owner         `protobuf:"varint,14,opt,name=winner,proto3,enum=uttt.Owner" json:"winner,omitempty"`
+	Comment     string        `protobuf:"bytes,15,opt,name=comment,proto3" json:"comment,omitempty"`
+}
+
+func (x *GameRecord) Reset() {
+	*x = GameRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_board_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameRecord) ProtoMessage() {}
+
+func (x *GameRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_board_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameRecord.ProtoReflect.Descriptor instead.
+func (*GameRecord) Descriptor() ([]byte, []int) {
+	return file_board_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GameRecord) GetPlayer1() *PlayerInfo {
+	if x != nil {
+		return x.Player1
+	}
+	return nil
+}
+
+func (x *GameRecord) GetPlayer2() *PlayerInfo {
+	if x != nil {
+		return x.Player2
+	}
+	return nil
+}
+
+func (x *GameRecord) GetStarted() int64 {
+	if x != nil {
+		return x.Started
+	}
+	return 0
+}
+
+func (x *GameRecord) GetRules() string {
+	if x != nil {
+		return x.Rules
+	}
+	return ""
+}
+
+func (x *GameRecord) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *GameRecord) GetCols() int32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+func (x *GameRecord) GetInarow() int32 {
+	if x != nil {
+		return x.Inarow
+	}
+	return 0
+}
+
+func (x *GameRecord) GetLevels() int32 {
+	if x != nil {
+		return x.Levels
+	}
+	return 0
+}
+
+func (x *GameRecord) GetOpening() string {
+	if x != nil {
+		return x.Opening
+	}
+	return ""
+}
+
+func (x *GameRecord) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *GameRecord) GetTimeControl() int64 {
+	if x != nil {
+		return x.TimeControl
+	}
+	return 0
+}
+
+func (x *GameRecord) GetMoves() []*MoveRecord {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+func (x *GameRecord) GetResult() Result {
+	if x != nil {
+		return x.Result
+	}
+	return Result_ONGOING
+}
+
+func (x *GameRecord) GetWinner() Owner {
+	if x != nil {
+		return x.Winner
+	}
+	return Owner_NONE
+}
+
+func (x *GameRecord) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+var File_board_proto protoreflect.FileDescriptor
+
+var file_board_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x75,
+	0x74, 0x74, 0x74, 0x22, 0x2b, 0x0a, 0x05, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x12, 0x10, 0x0a, 0x03,
+	0x72, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x72, 0x6f, 0x77, 0x12, 0x10,
+	0x0a, 0x03, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c,
+	0x22, 0x6b, 0x0a, 0x04, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x6c, 0x61, 0x72, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43,
+	0x6f, 0x6f, 0x72, 0x64, 0x52, 0x05, 0x6c, 0x61, 0x72, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73,
+	0x6d, 0x61, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74,
+	0x74, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x52, 0x05, 0x73, 0x6d, 0x61, 0x6c, 0x6c, 0x12, 0x1d,
+	0x0a, 0x03, 0x6d, 0x69, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74,
+	0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x52, 0x03, 0x6d, 0x69, 0x64, 0x22, 0x26, 0x0a,
+	0x05, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x03, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x52, 0x03, 0x76, 0x61, 0x6c, 0x22, 0x72, 0x0a, 0x04, 0x43, 0x65, 0x6c, 0x6c, 0x12, 0x23, 0x0a,
+	0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x75, 0x74, 0x74, 0x74, 0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x06, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x73, 0x12, 0x23, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52,
+	0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43, 0x65,
+	0x6c, 0x6c, 0x52, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x22, 0xd1, 0x01, 0x0a, 0x05, 0x42, 0x6f,
+	0x61, 0x72, 0x64, 0x12, 0x20, 0x0a, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43, 0x65, 0x6c, 0x6c, 0x52, 0x05,
+	0x63, 0x65, 0x6c, 0x6c, 0x73, 0x12, 0x25, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x43, 0x65, 0x6c, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43, 0x6f,
+	0x6f, 0x72, 0x64, 0x52, 0x07, 0x63, 0x75, 0x72, 0x43, 0x65, 0x6c, 0x6c, 0x12, 0x12, 0x0a, 0x04,
+	0x72, 0x6f, 0x77, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x63, 0x6f, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x6e, 0x61, 0x72, 0x6f, 0x77, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69, 0x6e, 0x61, 0x72, 0x6f, 0x77, 0x12, 0x16, 0x0a, 0x06,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x73, 0x12, 0x27, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x43, 0x65, 0x6c, 0x6c, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43, 0x6f,
+	0x6f, 0x72, 0x64, 0x52, 0x08, 0x63, 0x75, 0x72, 0x43, 0x65, 0x6c, 0x6c, 0x73, 0x22, 0xa0, 0x02,
+	0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x21,
+	0x0a, 0x05, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x75, 0x74, 0x74, 0x74, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x05, 0x62, 0x6f, 0x61, 0x72,
 	0x64, 0x12, 0x2b, 0x0a, 0x0a, 0x63, 0x65, 0x6c, 0x6c, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x18,
 	0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e,
 	0x65, 0x72, 0x52, 0x0a, 0x63, 0x65, 0x6c, 0x6c, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x1f,
@@ -589,19 +1428,124 @@ var file_board_proto_rawDesc = []byte{
 	0x28, 0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x2a, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x69,
 	0x64, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75,
 	0x74, 0x74, 0x74, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x6d,
-	0x6f, 0x76, 0x65, 0x73, 0x22, 0x2f, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x52,
-	0x04, 0x6d, 0x6f, 0x76, 0x65, 0x22, 0x4f, 0x0a, 0x0d, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x2a, 0x2b, 0x0a, 0x05, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12,
-	0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41,
-	0x59, 0x45, 0x52, 0x31, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41, 0x59, 0x45, 0x52,
-	0x32, 0x10, 0x02, 0x42, 0x0b, 0x5a, 0x09, 0x70, 0x6b, 0x67, 0x2f, 0x62, 0x6f, 0x61, 0x72, 0x64,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x76, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x22, 0x2f, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1e, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x76,
+	0x65, 0x22, 0x2b, 0x0a, 0x04, 0x50, 0x61, 0x74, 0x68, 0x12, 0x23, 0x0a, 0x06, 0x63, 0x6f, 0x6f,
+	0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74,
+	0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x73, 0x22, 0x71,
+	0x0a, 0x0d, 0x54, 0x68, 0x72, 0x65, 0x61, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x23, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x04, 0x63, 0x65, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04,
+	0x63, 0x65, 0x6c, 0x6c, 0x12, 0x1b, 0x0a, 0x02, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x52, 0x02, 0x61,
+	0x74, 0x22, 0xb1, 0x02, 0x0a, 0x11, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x23, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4f,
+	0x77, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x08,
+	0x63, 0x61, 0x70, 0x74, 0x75, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a,
+	0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x08, 0x63, 0x61, 0x70, 0x74,
+	0x75, 0x72, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x05, 0x64, 0x72, 0x61, 0x77, 0x6e, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x05, 0x64, 0x72, 0x61, 0x77, 0x6e, 0x12, 0x24, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x23, 0x0a, 0x06,
+	0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75,
+	0x74, 0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x65, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x04, 0x66, 0x72, 0x65, 0x65, 0x12, 0x1f, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64,
+	0x52, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x74, 0x68, 0x72, 0x65, 0x61, 0x74,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x54,
+	0x68, 0x72, 0x65, 0x61, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x74, 0x68,
+	0x72, 0x65, 0x61, 0x74, 0x73, 0x22, 0xa6, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x24, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x2f, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x75, 0x74, 0x74, 0x74, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x3a,
+	0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x50, 0x0a, 0x0a, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x22, 0xcf, 0x01, 0x0a,
+	0x0a, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x1e, 0x0a, 0x04, 0x6d,
+	0x6f, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x74, 0x74, 0x74,
+	0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x23, 0x0a, 0x06, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75, 0x74,
+	0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x69, 0x6e, 0x6b, 0x54, 0x69, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x68, 0x69, 0x6e, 0x6b, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x30, 0x0a, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xd1,
+	0x03, 0x0a, 0x0a, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x2a, 0x0a,
+	0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x31, 0x12, 0x2a, 0x0a, 0x07, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x75, 0x74, 0x74,
+	0x74, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x32, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x6c,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x6c, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x69, 0x6e, 0x61, 0x72, 0x6f, 0x77, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69,
+	0x6e, 0x61, 0x72, 0x6f, 0x77, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x26, 0x0a, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x18, 0x0c,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4d, 0x6f, 0x76, 0x65,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x12, 0x24, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e,
+	0x75, 0x74, 0x74, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x23, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x75, 0x74, 0x74, 0x74, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x2a, 0x2b, 0x0a, 0x05, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x08, 0x0a, 0x04, 0x4e,
+	0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x31,
+	0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x32, 0x10, 0x02, 0x2a,
+	0x7b, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0b, 0x0a, 0x07, 0x4f, 0x4e, 0x47,
+	0x4f, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x4c, 0x41, 0x59, 0x45, 0x52,
+	0x31, 0x5f, 0x57, 0x49, 0x4e, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x4c, 0x41, 0x59, 0x45,
+	0x52, 0x32, 0x5f, 0x57, 0x49, 0x4e, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x52, 0x41, 0x57,
+	0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x45, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x05,
+	0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4f, 0x52, 0x46, 0x45, 0x49, 0x54, 0x10, 0x06, 0x12, 0x0d, 0x0a,
+	0x09, 0x41, 0x42, 0x41, 0x4e, 0x44, 0x4f, 0x4e, 0x45, 0x44, 0x10, 0x07, 0x2a, 0x7c, 0x0a, 0x06,
+	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x43, 0x45,
+	0x4c, 0x4c, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x50, 0x41, 0x43, 0x45, 0x5f, 0x54, 0x41,
+	0x4b, 0x45, 0x4e, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x45, 0x4c, 0x4c, 0x5f, 0x43, 0x4c,
+	0x4f, 0x53, 0x45, 0x44, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x55, 0x54, 0x5f, 0x4f, 0x46,
+	0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x47, 0x41, 0x4d, 0x45,
+	0x5f, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x05, 0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x4f, 0x54, 0x5f, 0x59,
+	0x4f, 0x55, 0x52, 0x5f, 0x54, 0x55, 0x52, 0x4e, 0x10, 0x06, 0x42, 0x0b, 0x5a, 0x09, 0x70, 0x6b,
+	0x67, 0x2f, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -616,38 +1560,73 @@ func file_board_proto_rawDescGZIP() []byte {
 	return file_board_proto_rawDescData
 }
 
-var file_board_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_board_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_board_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_board_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_board_proto_goTypes = []interface{}{
-	(Owner)(0),            // 0: uttt.Owner
-	(*Coord)(nil),         // 1: uttt.Coord
-	(*Move)(nil),          // 2: uttt.Move
-	(*Space)(nil),         // 3: uttt.Space
-	(*Cell)(nil),          // 4: uttt.Cell
-	(*Board)(nil),         // 5: uttt.Board
-	(*StateMessage)(nil),  // 6: uttt.StateMessage
-	(*ActionMessage)(nil), // 7: uttt.ActionMessage
-	(*ReturnMessage)(nil), // 8: uttt.ReturnMessage
+	(Owner)(0),                // 0: uttt.Owner
+	(Result)(0),               // 1: uttt.Result
+	(Reason)(0),               // 2: uttt.Reason
+	(*Coord)(nil),             // 3: uttt.Coord
+	(*Move)(nil),              // 4: uttt.Move
+	(*Space)(nil),             // 5: uttt.Space
+	(*Cell)(nil),              // 6: uttt.Cell
+	(*Board)(nil),             // 7: uttt.Board
+	(*StateMessage)(nil),      // 8: uttt.StateMessage
+	(*ActionMessage)(nil),     // 9: uttt.ActionMessage
+	(*Path)(nil),              // 10: uttt.Path
+	(*ThreatMessage)(nil),     // 11: uttt.ThreatMessage
+	(*MoveResultMessage)(nil), // 12: uttt.MoveResultMessage
+	(*ReturnMessage)(nil),     // 13: uttt.ReturnMessage
+	(*PlayerInfo)(nil),        // 14: uttt.PlayerInfo
+	(*Evaluation)(nil),        // 15: uttt.Evaluation
+	(*MoveRecord)(nil),        // 16: uttt.MoveRecord
+	(*GameRecord)(nil),        // 17: uttt.GameRecord
 }
 var file_board_proto_depIdxs = []int32{
-	1,  // 0: uttt.Move.large:type_name -> uttt.Coord
-	1,  // 1: uttt.Move.small:type_name -> uttt.Coord
-	0,  // 2: uttt.Space.val:type_name -> uttt.Owner
-	3,  // 3: uttt.Cell.spaces:type_name -> uttt.Space
-	4,  // 4: uttt.Board.cells:type_name -> uttt.Cell
-	1,  // 5: uttt.Board.curCell:type_name -> uttt.Coord
-	5,  // 6: uttt.StateMessage.board:type_name -> uttt.Board
-	0,  // 7: uttt.StateMessage.cellowners:type_name -> uttt.Owner
-	0,  // 8: uttt.StateMessage.turn:type_name -> uttt.Owner
-	0,  // 9: uttt.StateMessage.winner:type_name -> uttt.Owner
-	2,  // 10: uttt.StateMessage.validmoves:type_name -> uttt.Move
-	2,  // 11: uttt.ActionMessage.move:type_name -> uttt.Move
-	6,  // 12: uttt.ReturnMessage.state:type_name -> uttt.StateMessage
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	3,  // 0: uttt.Move.large:type_name -> uttt.Coord
+	3,  // 1: uttt.Move.small:type_name -> uttt.Coord
+	3,  // 2: uttt.Move.mid:type_name -> uttt.Coord
+	0,  // 3: uttt.Space.val:type_name -> uttt.Owner
+	5,  // 4: uttt.Cell.spaces:type_name -> uttt.Space
+	0,  // 5: uttt.Cell.winner:type_name -> uttt.Owner
+	6,  // 6: uttt.Cell.cells:type_name -> uttt.Cell
+	6,  // 7: uttt.Board.cells:type_name -> uttt.Cell
+	3,  // 8: uttt.Board.curCell:type_name -> uttt.Coord
+	3,  // 9: uttt.Board.curCells:type_name -> uttt.Coord
+	7,  // 10: uttt.StateMessage.board:type_name -> uttt.Board
+	0,  // 11: uttt.StateMessage.cellowners:type_name -> uttt.Owner
+	0,  // 12: uttt.StateMessage.turn:type_name -> uttt.Owner
+	0,  // 13: uttt.StateMessage.winner:type_name -> uttt.Owner
+	4,  // 14: uttt.StateMessage.validmoves:type_name -> uttt.Move
+	1,  // 15: uttt.StateMessage.result:type_name -> uttt.Result
+	4,  // 16: uttt.ActionMessage.move:type_name -> uttt.Move
+	3,  // 17: uttt.Path.coords:type_name -> uttt.Coord
+	0,  // 18: uttt.ThreatMessage.player:type_name -> uttt.Owner
+	10, // 19: uttt.ThreatMessage.cell:type_name -> uttt.Path
+	3,  // 20: uttt.ThreatMessage.at:type_name -> uttt.Coord
+	0,  // 21: uttt.MoveResultMessage.player:type_name -> uttt.Owner
+	10, // 22: uttt.MoveResultMessage.captured:type_name -> uttt.Path
+	10, // 23: uttt.MoveResultMessage.drawn:type_name -> uttt.Path
+	1,  // 24: uttt.MoveResultMessage.result:type_name -> uttt.Result
+	0,  // 25: uttt.MoveResultMessage.winner:type_name -> uttt.Owner
+	3,  // 26: uttt.MoveResultMessage.next:type_name -> uttt.Coord
+	11, // 27: uttt.MoveResultMessage.threats:type_name -> uttt.ThreatMessage
+	8,  // 28: uttt.ReturnMessage.state:type_name -> uttt.StateMessage
+	2,  // 29: uttt.ReturnMessage.reason:type_name -> uttt.Reason
+	12, // 30: uttt.ReturnMessage.result:type_name -> uttt.MoveResultMessage
+	4,  // 31: uttt.MoveRecord.move:type_name -> uttt.Move
+	0,  // 32: uttt.MoveRecord.player:type_name -> uttt.Owner
+	15, // 33: uttt.MoveRecord.evaluation:type_name -> uttt.Evaluation
+	14, // 34: uttt.GameRecord.player1:type_name -> uttt.PlayerInfo
+	14, // 35: uttt.GameRecord.player2:type_name -> uttt.PlayerInfo
+	16, // 36: uttt.GameRecord.moves:type_name -> uttt.MoveRecord
+	1,  // 37: uttt.GameRecord.result:type_name -> uttt.Result
+	0,  // 38: uttt.GameRecord.winner:type_name -> uttt.Owner
+	39, // [39:39] is the sub-list for method output_type
+	39, // [39:39] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_board_proto_init() }
@@ -741,6 +1720,42 @@ func file_board_proto_init() {
 			}
 		}
 		file_board_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Path); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_board_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ThreatMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_board_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_board_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReturnMessage); i {
 			case 0:
 				return &v.state
@@ -752,14 +1767,62 @@ func file_board_proto_init() {
 				return nil
 			}
 		}
+		file_board_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_board_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Evaluation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_board_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_board_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_board_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   8,
+			NumEnums:      3,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
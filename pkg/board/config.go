@@ -1,16 +1,229 @@
 package board
 
+import "fmt"
+
 // board related constants
 const (
-	ROWS  = 3
-	COLS  = 3
-	CELLS = ROWS * COLS
+	// the size of a board when none is given
+	DEFAULT_ROWS   = 3
+	DEFAULT_COLS   = 3
+	DEFAULT_LEVELS = 2
+
+	// the most rows or columns a board can have;
+	// a cell has to fit in a 64 bit mask
+	MAX_SIDE  = 8
+	MAX_CELLS = MAX_SIDE * MAX_SIDE
+
+	// the deepest cells can nest, and the most spaces a board can have
+	MAX_LEVELS = 5
+	MAX_SPACES = 1 << 18
 )
 
 // protobuf related constants
 const (
-	STATE_PORT   = "8000"
-	ACTION_PORT  = "8001"
-	RETURN_PORT  = "8002"
-	MAX_MSG_SIZE = 1200
+	STATE_PORT  = "8000"
+	ACTION_PORT = "8001"
+	RETURN_PORT = "8002"
+	// the most bytes an action message can take; state messages are
+	// as large as the board needs, since every message is preceded by
+	// its size
+	MAX_MSG_SIZE = 65536
 )
+
+// ========== Size ==========
+
+// Size is the shape of a game. Every cell has Rows x Cols spaces,
+// the board has Rows x Cols cells, and InARow marks in a line
+// win a cell, or InARow cells in a line win the board.
+// Levels is how deep cells nest; the standard game has 2 levels,
+// while on a 3 level board every cell holds Rows x Cols smaller cells
+type Size struct {
+	Rows, Cols, InARow, Levels int
+}
+
+// the standard 3x3 game
+func DefaultSize() Size {
+	return Size{Rows: DEFAULT_ROWS, Cols: DEFAULT_COLS, InARow: minInt(DEFAULT_ROWS, DEFAULT_COLS), Levels: DEFAULT_LEVELS}
+}
+
+// returns the size with the fields that are 0 set to those of the
+// standard game, except InARow, which defaults to the shorter side
+func (s Size) WithDefaults() Size {
+	if s.Rows == 0 {
+		s.Rows = DEFAULT_ROWS
+	}
+	if s.Cols == 0 {
+		s.Cols = DEFAULT_COLS
+	}
+	if s.InARow == 0 {
+		s.InARow = minInt(s.Rows, s.Cols)
+	}
+	if s.Levels == 0 {
+		s.Levels = DEFAULT_LEVELS
+	}
+	return s
+}
+
+// returns an error if the size can't be played
+func (s Size) Validate() error {
+	if s.Rows < 1 || s.Cols < 1 || s.Rows > MAX_SIDE || s.Cols > MAX_SIDE {
+		return fmt.Errorf("a board needs between 1 and %d rows and columns, not %dx%d", MAX_SIDE, s.Rows, s.Cols)
+	}
+	if s.InARow < 1 || s.InARow > maxInt(s.Rows, s.Cols) {
+		return fmt.Errorf("a %dx%d board needs between 1 and %d in a row, not %d", s.Rows, s.Cols, maxInt(s.Rows, s.Cols), s.InARow)
+	}
+	if s.Levels < 2 || s.Levels > MAX_LEVELS {
+		return fmt.Errorf("a board needs between 2 and %d levels, not %d", MAX_LEVELS, s.Levels)
+	}
+	if s.Spaces() > MAX_SPACES {
+		return fmt.Errorf("a %dx%d board with %d levels has more than %d spaces", s.Rows, s.Cols, s.Levels, MAX_SPACES)
+	}
+	return nil
+}
+
+// the number of spaces in a cell, which is also the number of cells
+// in the board, or in any cell holding cells
+func (s Size) Cells() int {
+	return s.Rows * s.Cols
+}
+
+// the number of spaces on the whole board
+func (s Size) Spaces() int {
+	n := 1
+	for level := 0; level < s.Levels && n <= MAX_SPACES; level++ {
+		n *= s.Cells()
+	}
+	return n
+}
+
+// whether or not the coordinate is on a board of this size
+func (s Size) Contains(c *Coord) bool {
+	return c.Valid() && c.Row < int32(s.Rows) && c.Col < int32(s.Cols)
+}
+
+// converts the coordinate to a 1D array index in row-major order
+func (s Size) Index(c *Coord) (idx uint32, valid bool) {
+	if !s.Contains(c) {
+		return 0, false
+	}
+	return uint32(c.Row)*uint32(s.Cols) + uint32(c.Col), true
+}
+
+// converts a 1D array index to a coordinate
+func (s Size) Coord(idx uint32) *Coord {
+	return &Coord{Row: int32(idx / uint32(s.Cols)), Col: int32(idx % uint32(s.Cols))}
+}
+
+// converts a path of coordinates to the index of the cell (or space) it
+// leads to among all the cells at its level, in row-major order level by level
+func (s Size) PathIndex(path []*Coord) (idx uint32, valid bool) {
+	for _, c := range path {
+		i, valid := s.Index(c)
+		if !valid {
+			return 0, false
+		}
+		idx = idx*uint32(s.Cells()) + i
+	}
+	return idx, true
+}
+
+// converts an index among the cells (or spaces) of a level to the path
+// of coordinates leading to it, where depth is the length of the path
+func (s Size) Path(idx uint32, depth int) []*Coord {
+	path := make([]*Coord, depth)
+	for i := depth - 1; i >= 0; i-- {
+		path[i] = s.Coord(idx % uint32(s.Cells()))
+		idx /= uint32(s.Cells())
+	}
+	return path
+}
+
+// converts a move to the index of its space among all the spaces of
+// the board, in row-major order level by level
+func (s Size) MoveIndex(m *Move) (idx uint32, valid bool) {
+	if len(m.GetMid()) != s.Levels-2 {
+		return 0, false
+	}
+	idx, valid = s.Index(m.GetLarge())
+	for _, c := range m.GetMid() {
+		i, ok := s.Index(c)
+		idx, valid = idx*uint32(s.Cells())+i, valid && ok
+	}
+	i, ok := s.Index(m.GetSmall())
+	return idx*uint32(s.Cells()) + i, valid && ok
+}
+
+// converts the index of a space among all the spaces of the board
+// to the move that claims it
+func (s Size) Move(idx uint32) *Move {
+	return NewMove(s.Path(idx, s.Levels)...)
+}
+
+// the number of rows and columns of spaces across the whole board
+func (s Size) Grid() (height, width int) {
+	height, width = 1, 1
+	for i := 0; i < s.Levels; i++ {
+		height, width = height*s.Rows, width*s.Cols
+	}
+	return
+}
+
+// the index of the space in the given row and column of spaces across
+// the whole board, counting from the top left
+func (s Size) GridIndex(row, col int) uint32 {
+	var idx uint32
+	height, width := s.Grid()
+	for i := 0; i < s.Levels; i++ {
+		height, width = height/s.Rows, width/s.Cols
+		idx = idx*uint32(s.Cells()) + uint32(row/height%s.Rows*s.Cols+col/width%s.Cols)
+	}
+	return idx
+}
+
+// the row and column across the whole board of the space with the
+// given index; the inverse of GridIndex
+func (s Size) GridCoord(idx uint32) (row, col int) {
+	for _, c := range s.Path(idx, s.Levels) {
+		row, col = row*s.Rows+int(c.Row), col*s.Cols+int(c.Col)
+	}
+	return
+}
+
+// the level and index of the cell the next move has to be made in given
+// a board's curCell and curCells; level 0 if it can be made anywhere.
+// Anything after the first coordinate that's off the board is ignored
+func (s Size) target(cur *Coord, cells []*Coord) (level int, idx uint32) {
+	idx, valid := s.Index(cur)
+	if !valid {
+		return 0, 0
+	}
+	level = 1
+	for _, c := range cells {
+		i, valid := s.Index(c)
+		if !valid || level == s.Levels-1 {
+			break
+		}
+		idx, level = idx*uint32(s.Cells())+i, level+1
+	}
+	return level, idx
+}
+
+func (s Size) String() string {
+	if s.Levels != DEFAULT_LEVELS {
+		return fmt.Sprintf("%dx%d, %d in a row, %d levels", s.Rows, s.Cols, s.InARow, s.Levels)
+	}
+	return fmt.Sprintf("%dx%d, %d in a row", s.Rows, s.Cols, s.InARow)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,142 @@
+package board
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ========== Move Conventions ==========
+// Other programs and sites write moves in other ways than ours, which
+// is the indices of the move's coordinates split by . (see
+// Size.MoveString). The ones moves can be converted to and from are
+// those of Convention. On a 3x3 board, the move to the top right space
+// of the center cell is 4.2 in ours, 32 as an index, 38 as a cell index
+// and 3,5 as a row and column.
+
+// Convention is a way of writing moves
+type Convention int
+
+const (
+	// ours, see Size.MoveString
+	NativeConvention Convention = iota
+	// the space's index across the whole board in row-major order,
+	// 0 to 80 on a 3x3 board
+	IndexConvention
+	// the cell's index times the spaces in a cell plus the space's
+	// index in the cell; the index of the space in Size.MoveIndex
+	CellIndexConvention
+	// the space's row and column across the whole board, each 0 to 8
+	// on a 3x3 board, split by a comma
+	RowColConvention
+)
+
+// every convention, in the order of their names
+var conventionNames = []string{"native", "index", "cell-index", "rowcol"}
+
+// the names of the conventions, for help messages
+func ConventionNames() string {
+	return strings.Join(conventionNames, ", ")
+}
+
+// parses the name of a convention
+func ParseConvention(name string) (Convention, error) {
+	for i, n := range conventionNames {
+		if n == name {
+			return Convention(i), nil
+		}
+	}
+	return NativeConvention, fmt.Errorf("unknown convention %q, expected one of %s", name, ConventionNames())
+}
+
+func (c Convention) String() string {
+	return conventionNames[c]
+}
+
+// FormatMove writes the move in the convention
+func (s Size) FormatMove(m *Move, c Convention) string {
+	idx, _ := s.MoveIndex(m)
+	switch c {
+	case IndexConvention:
+		row, col := s.GridCoord(idx)
+		_, width := s.Grid()
+		return strconv.Itoa(row*width + col)
+	case CellIndexConvention:
+		return strconv.FormatUint(uint64(idx), 10)
+	case RowColConvention:
+		row, col := s.GridCoord(idx)
+		return fmt.Sprintf("%d,%d", row, col)
+	}
+	return s.MoveString(m)
+}
+
+// FormatMoves writes the moves in the convention, split by spaces
+func (s Size) FormatMoves(moves []*Move, c Convention) string {
+	strs := make([]string, len(moves))
+	for i, m := range moves {
+		strs[i] = s.FormatMove(m, c)
+	}
+	return strings.Join(strs, " ")
+}
+
+// the numbers in a list of moves in a convention other than ours, which
+// can be split by anything, like "[40, 30]" or "(4, 4) (3, 3)"
+var conventionNumber = regexp.MustCompile(`-?\d+`)
+
+// ParseMoves reads a list of moves in the convention. Our moves are
+// split by spaces or commas; the numbers of the others can be split by
+// anything that isn't a digit
+func (s Size) ParseMoves(text string, c Convention) ([]*Move, error) {
+	if c == NativeConvention {
+		var moves []*Move
+		for _, str := range strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		}) {
+			m, err := s.ParseMove(str)
+			if err != nil {
+				return nil, err
+			}
+			moves = append(moves, m)
+		}
+		return moves, nil
+	}
+
+	strs := conventionNumber.FindAllString(text, -1)
+	nums := make([]int, len(strs))
+	for i, str := range strs {
+		num, err := strconv.Atoi(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", str)
+		}
+		nums[i] = num
+	}
+	height, width := s.Grid()
+
+	var moves []*Move
+	switch c {
+	case IndexConvention, CellIndexConvention:
+		for _, num := range nums {
+			if num < 0 || num >= height*width {
+				return nil, fmt.Errorf("invalid move %d, expected an index from 0 to %d", num, height*width-1)
+			}
+			idx := uint32(num)
+			if c == IndexConvention {
+				idx = s.GridIndex(num/width, num%width)
+			}
+			moves = append(moves, s.Move(idx))
+		}
+	case RowColConvention:
+		if len(nums)%2 != 0 {
+			return nil, fmt.Errorf("expected pairs of a row and a column, got %d numbers", len(nums))
+		}
+		for i := 0; i < len(nums); i += 2 {
+			row, col := nums[i], nums[i+1]
+			if row < 0 || row >= height || col < 0 || col >= width {
+				return nil, fmt.Errorf("invalid move %d,%d, expected a row from 0 to %d and a column from 0 to %d", row, col, height-1, width-1)
+			}
+			moves = append(moves, s.Move(s.GridIndex(row, col)))
+		}
+	}
+	return moves, nil
+}
@@ -0,0 +1,130 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+// the moves written in our convention
+func nativeStrings(s Size, moves []*Move) []string {
+	strs := make([]string, len(moves))
+	for i, m := range moves {
+		strs[i] = s.MoveString(m)
+	}
+	return strs
+}
+
+// the example in the README: the top right space of the center cell
+func TestConventionExample(t *testing.T) {
+	s := DefaultSize()
+	m, err := s.ParseMove("4.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for c, want := range map[Convention]string{NativeConvention: "4.2", IndexConvention: "32", CellIndexConvention: "38", RowColConvention: "3,5"} {
+		if got := s.FormatMove(m, c); got != want {
+			t.Errorf("4.2 is %s as %v, expected %s", got, c, want)
+		}
+		moves, err := s.ParseMoves(want, c)
+		if err != nil {
+			t.Errorf("%s as %v: %v", want, c, err)
+			continue
+		}
+		if got := nativeStrings(s, moves); !reflect.DeepEqual(got, []string{"4.2"}) {
+			t.Errorf("%s as %v read as %v, expected [4.2]", want, c, got)
+		}
+	}
+}
+
+// every space of every size reads back as itself in every convention
+func TestConventionRoundTrip(t *testing.T) {
+	for _, s := range []Size{
+		DefaultSize(),
+		{Rows: 4, Cols: 4, InARow: 3, Levels: 2},
+		{Rows: 2, Cols: 3, InARow: 2, Levels: 2},
+		{Rows: 3, Cols: 3, InARow: 3, Levels: 3},
+	} {
+		moves := make([]*Move, s.Spaces())
+		for i := range moves {
+			moves[i] = s.Move(uint32(i))
+		}
+		for c := range conventionNames {
+			text := s.FormatMoves(moves, Convention(c))
+			got, err := s.ParseMoves(text, Convention(c))
+			if err != nil {
+				t.Errorf("%v as %v: %v", s, Convention(c), err)
+				continue
+			}
+			if !reflect.DeepEqual(nativeStrings(s, got), nativeStrings(s, moves)) {
+				t.Errorf("%v as %v: read %s back as %v", s, Convention(c), text, nativeStrings(s, got))
+			}
+		}
+	}
+}
+
+func TestParseMoves(t *testing.T) {
+	tests := []struct {
+		c    Convention
+		text string
+		want []string
+	}{
+		{NativeConvention, "4.2 4.4", []string{"4.2", "4.4"}},
+		{NativeConvention, "4.2,4.4", []string{"4.2", "4.4"}},
+		{NativeConvention, " 4.2,\t4.4\r\n0.0 ", []string{"4.2", "4.4", "0.0"}},
+		{IndexConvention, "32 40", []string{"4.2", "4.4"}},
+		{IndexConvention, "[32, 40]", []string{"4.2", "4.4"}},
+		{IndexConvention, "32\n40;0", []string{"4.2", "4.4", "0.0"}},
+		{CellIndexConvention, "38,40", []string{"4.2", "4.4"}},
+		{CellIndexConvention, "move 38 then 80", []string{"4.2", "8.8"}},
+		{RowColConvention, "3,5", []string{"4.2"}},
+		{RowColConvention, "(3, 5) (0, 6)", []string{"4.2", "2.0"}},
+		{RowColConvention, "[[3,5],[8,8]]", []string{"4.2", "8.8"}},
+		{RowColConvention, "3 5\n4 4", []string{"4.2", "4.4"}},
+		{IndexConvention, "", []string{}},
+	}
+	s := DefaultSize()
+	for _, test := range tests {
+		moves, err := s.ParseMoves(test.text, test.c)
+		if err != nil {
+			t.Errorf("%q as %v: %v", test.text, test.c, err)
+			continue
+		}
+		if got := nativeStrings(s, moves); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q as %v read as %v, expected %v", test.text, test.c, got, test.want)
+		}
+	}
+}
+
+func TestParseMovesInvalid(t *testing.T) {
+	tests := []struct {
+		c    Convention
+		text string
+	}{
+		{NativeConvention, "9.0"},
+		{NativeConvention, "4"},
+		{NativeConvention, "4-2"},
+		{IndexConvention, "81"},
+		{IndexConvention, "-1"},
+		{CellIndexConvention, "81"},
+		{RowColConvention, "3,5,1"},
+		{RowColConvention, "9,0"},
+		{RowColConvention, "0,-1"},
+	}
+	s := DefaultSize()
+	for _, test := range tests {
+		if moves, err := s.ParseMoves(test.text, test.c); err == nil {
+			t.Errorf("%q as %v read as %v without an error", test.text, test.c, nativeStrings(s, moves))
+		}
+	}
+}
+
+func TestParseConvention(t *testing.T) {
+	for i, name := range conventionNames {
+		if c, err := ParseConvention(name); err != nil || c != Convention(i) || c.String() != name {
+			t.Errorf("ParseConvention(%q) = %v, %v", name, c, err)
+		}
+	}
+	if _, err := ParseConvention("algebraic"); err == nil {
+		t.Error("parsed an unknown convention")
+	}
+}
@@ -0,0 +1,51 @@
+package board
+
+// ========== Game ==========
+
+// A Game is a position along with the moves that led to it
+type Game struct {
+	start   *Position
+	pos     *Position
+	history []*Move
+	undo    []UndoRecord
+}
+
+// starts a game from the position, which the game takes ownership of
+func NewGame(p *Position) *Game {
+	return &Game{start: p.Clone(), pos: p}
+}
+
+// the current position. It changes as moves are played, so clone it to keep it
+func (g *Game) Position() *Position {
+	return g.pos
+}
+
+// the position the game started from
+func (g *Game) Start() *Position {
+	return g.start
+}
+
+// plays the move for the player whose turn it is and returns what it
+// changed. The move should already have been checked with Position.Legal
+func (g *Game) Play(m *Move) MoveResult {
+	rec := g.pos.Apply(m)
+	g.undo = append(g.undo, rec)
+	g.history = append(g.history, m)
+	return g.pos.Describe(rec)
+}
+
+// takes back the last move, returning false if there isn't one
+func (g *Game) Takeback() bool {
+	if len(g.undo) == 0 {
+		return false
+	}
+	last := len(g.undo) - 1
+	g.pos.Undo(g.undo[last])
+	g.undo, g.history = g.undo[:last], g.history[:last]
+	return true
+}
+
+// the moves played so far, in order
+func (g *Game) History() []*Move {
+	return g.history
+}
@@ -0,0 +1,189 @@
+package board
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ========== Images ==========
+// Raster images are drawn with anti-aliased shapes on an RGBA image,
+// which can be written as a PNG. Games become animated GIFs with a
+// frame per position, drawn with a palette of the rendering colors
+// and blends between them.
+
+// the number of blends between every pair of colors in the GIF palette
+const GIF_BLENDS = 7
+
+// rasterCanvas draws on an RGBA image
+type rasterCanvas struct {
+	img *image.RGBA
+}
+
+// parses a RENDER_ color
+func hexColor(s string) color.RGBA {
+	v, _ := strconv.ParseUint(s[1:], 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+// mixes a into b by t, between 0 (all b) and 1 (all a)
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x)*t + float64(y)*(1-t)))
+	}
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 0xff}
+}
+
+// blends the color into the pixel by coverage, between 0 and 1
+func (c *rasterCanvas) paint(x, y int, col color.RGBA, coverage float64) {
+	if coverage <= 0 || !(image.Point{x, y}).In(c.img.Rect) {
+		return
+	}
+	c.img.SetRGBA(x, y, blend(col, c.img.RGBAAt(x, y), math.Min(coverage, 1)))
+}
+
+// calls fn with every pixel that might be within pad of the box
+func (c *rasterCanvas) each(x1, y1, x2, y2, pad float64, fn func(x, y int)) {
+	b := c.img.Rect
+	for y := maxInt(b.Min.Y, int(math.Floor(y1-pad))); y < minInt(b.Max.Y, int(math.Ceil(y2+pad))); y++ {
+		for x := maxInt(b.Min.X, int(math.Floor(x1-pad))); x < minInt(b.Max.X, int(math.Ceil(x2+pad))); x++ {
+			fn(x, y)
+		}
+	}
+}
+
+func (c *rasterCanvas) fill(x, y, w, h float64, hex string, opacity float64) {
+	col := hexColor(hex)
+	c.each(x, y, x+w, y+h, 0, func(px, py int) {
+		// how much of the pixel the rectangle covers
+		cx := math.Min(x+w, float64(px+1)) - math.Max(x, float64(px))
+		cy := math.Min(y+h, float64(py+1)) - math.Max(y, float64(py))
+		if cx > 0 && cy > 0 {
+			c.paint(px, py, col, cx*cy*opacity)
+		}
+	})
+}
+
+func (c *rasterCanvas) lines(segments [][4]float64, width float64, hex string, opacity float64) {
+	col := hexColor(hex)
+	x1, y1, x2, y2 := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, s := range segments {
+		x1, y1 = math.Min(x1, math.Min(s[0], s[2])), math.Min(y1, math.Min(s[1], s[3]))
+		x2, y2 = math.Max(x2, math.Max(s[0], s[2])), math.Max(y2, math.Max(s[1], s[3]))
+	}
+	c.each(x1, y1, x2, y2, width/2+1, func(px, py int) {
+		// the distance from the pixel's center to the closest segment
+		cx, cy := float64(px)+0.5, float64(py)+0.5
+		d := math.Inf(1)
+		for _, s := range segments {
+			dx, dy := s[2]-s[0], s[3]-s[1]
+			t := 0.0
+			if length := dx*dx + dy*dy; length > 0 {
+				t = math.Max(0, math.Min(1, ((cx-s[0])*dx+(cy-s[1])*dy)/length))
+			}
+			d = math.Min(d, math.Hypot(cx-(s[0]+t*dx), cy-(s[1]+t*dy)))
+		}
+		c.paint(px, py, col, (width/2+0.5-d)*opacity)
+	})
+}
+
+func (c *rasterCanvas) ring(cx, cy, r, width float64, hex string, opacity float64) {
+	col := hexColor(hex)
+	c.each(cx-r, cy-r, cx+r, cy+r, width/2+1, func(px, py int) {
+		d := math.Abs(math.Hypot(float64(px)+0.5-cx, float64(py)+0.5-cy) - r)
+		c.paint(px, py, col, (width/2+0.5-d)*opacity)
+	})
+}
+
+// Image returns the position drawn as a raster image
+func (p *Position) Image(opts RenderOptions) *image.RGBA {
+	l := newLayout(p.size, opts)
+	width, height := l.bounds()
+	c := &rasterCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+	p.draw(c, l, opts)
+	return c.img
+}
+
+// GIF returns the game drawn as an animated GIF, with a frame for the
+// position it started from and one after every move, the last move
+// highlighted. Every frame shows for delay, and the last one for three
+// times as long. opts.LastMove is ignored
+func (g *Game) GIF(opts RenderOptions, delay time.Duration) *gif.GIF {
+	pal := gifPalette()
+	indices := map[color.RGBA]uint8{}
+	frame := func(p *Position) *image.Paletted {
+		img := p.Image(opts)
+		frame := image.NewPaletted(img.Rect, pal)
+		for i := 0; i < len(img.Pix); i += 4 {
+			col := color.RGBA{R: img.Pix[i], G: img.Pix[i+1], B: img.Pix[i+2], A: img.Pix[i+3]}
+			idx, ok := indices[col]
+			if !ok {
+				idx = uint8(pal.Index(col))
+				indices[col] = idx
+			}
+			frame.Pix[i/4] = idx
+		}
+		return frame
+	}
+
+	// in hundredths of a second
+	centis := int(delay / (10 * time.Millisecond))
+	p := g.start.Clone()
+	opts.LastMove = nil
+	prev := frame(p)
+	anim := &gif.GIF{Image: []*image.Paletted{prev}, Delay: []int{centis}}
+	for _, m := range g.history {
+		p.Apply(m)
+		opts.LastMove = m
+		next := frame(p)
+		// frames after the first only hold what changed
+		anim.Image = append(anim.Image, next.SubImage(changed(prev, next)).(*image.Paletted))
+		anim.Delay = append(anim.Delay, centis)
+		prev = next
+	}
+	anim.Delay[len(anim.Delay)-1] = 3 * centis
+	return anim
+}
+
+// the smallest rectangle holding every pixel that differs between the
+// frames, which have the same bounds; a single pixel if none do
+func changed(a, b *image.Paletted) image.Rectangle {
+	r := image.Rectangle{}
+	for y := a.Rect.Min.Y; y < a.Rect.Max.Y; y++ {
+		for x := a.Rect.Min.X; x < a.Rect.Max.X; x++ {
+			if a.ColorIndexAt(x, y) != b.ColorIndexAt(x, y) {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if r.Empty() {
+		return image.Rect(a.Rect.Min.X, a.Rect.Min.Y, a.Rect.Min.X+1, a.Rect.Min.Y+1)
+	}
+	return r
+}
+
+// the colors GIF frames are drawn with: every background, and every
+// mark and line blended into them
+func gifPalette() color.Palette {
+	white := hexColor(RENDER_BACKGROUND)
+	backgrounds := []color.RGBA{white, hexColor(RENDER_TARGET), hexColor(RENDER_LAST_MOVE), hexColor(RENDER_DRAWN)}
+	for _, t := range []float64{0.2, 0.4, 0.6, 0.8} {
+		backgrounds = append(backgrounds, blend(hexColor(RENDER_HEAT), white, t))
+	}
+
+	var pal color.Palette
+	for _, bg := range backgrounds {
+		pal = append(pal, bg)
+	}
+	for _, bg := range backgrounds {
+		for _, fg := range []string{RENDER_LINE, RENDER_X, RENDER_O} {
+			for i := 1; i <= GIF_BLENDS; i++ {
+				pal = append(pal, blend(hexColor(fg), bg, float64(i)/GIF_BLENDS))
+			}
+		}
+	}
+	return pal
+}
@@ -0,0 +1,77 @@
+package board
+
+// ========== Move Results ==========
+
+// MoveResult describes what a move changed
+type MoveResult struct {
+	Player Owner
+	// the cells the move won, innermost first
+	Captured [][]*Coord
+	// the cells that nobody can win anymore because of the move
+	Drawn [][]*Coord
+	// the result of the game after the move and who won, if anyone
+	Result Result
+	Winner Owner
+	// the cell the next move has to be made in, outermost first;
+	// nil if it can be made in any cell or the game is over
+	Next []*Coord
+	// the lines the move set up for Player, outermost cell first
+	Threats []Threat
+}
+
+// Describe returns what the move recorded by rec changed.
+// It has to be called right after Apply returned rec
+func (p *Position) Describe(rec UndoRecord) MoveResult {
+	res := MoveResult{Player: rec.Owner, Next: p.Target()}
+	res.Result, res.Winner = p.Result()
+	if res.Result != Result_ONGOING {
+		res.Next = nil
+	}
+
+	n := uint32(p.g.n)
+	player, _ := playerIndex(rec.Owner)
+
+	// the cells holding the space, innermost first
+	idx := rec.Space / n
+	for level := p.size.Levels - 1; level > 0; level, idx = level-1, idx/n {
+		prev, parent, bit := rec.prevNodes[level-1], p.node(level-1, idx/n), uint64(1)<<(idx%n)
+		switch {
+		case (prev.owned[0]|prev.owned[1])&bit == 0 && (parent.owned[0]|parent.owned[1])&bit != 0:
+			res.Captured = append(res.Captured, p.size.Path(idx, level))
+		case prev.closed&bit == 0 && parent.closed&bit != 0 && p.childOwner(level, idx) == Owner_NONE:
+			res.Drawn = append(res.Drawn, p.size.Path(idx, level))
+		}
+	}
+
+	if res.Result != Result_ONGOING {
+		return res
+	}
+	// lines can only be set up in the nodes holding the space,
+	// and only count while the node is open
+	for level := 0; level < p.size.Levels; level++ {
+		idx := rec.Space / p.counts[p.size.Levels-level]
+		if !p.open(level, idx) {
+			break
+		}
+		prev := rec.prevNodes[level]
+		added := p.threats(level, idx, player) &^ p.g.threats(prev.owned[player], p.available(prev))
+		res.Threats = p.appendThreats(res.Threats, level, idx, rec.Owner, added)
+	}
+	return res
+}
+
+// ToProto converts the move result to the message sent to clients
+func (res MoveResult) ToProto() *MoveResultMessage {
+	m := &MoveResultMessage{Player: res.Player, Result: res.Result, Winner: res.Winner, Next: res.Next}
+	m.Free = res.Result == Result_ONGOING && res.Next == nil
+	for _, cell := range res.Captured {
+		m.Captured = append(m.Captured, &Path{Coords: cell})
+	}
+	for _, cell := range res.Drawn {
+		m.Drawn = append(m.Drawn, &Path{Coords: cell})
+	}
+	for _, t := range res.Threats {
+		m.Threats = append(m.Threats, &ThreatMessage{Player: t.Player, Cell: &Path{Coords: t.Cell}, At: t.At})
+	}
+	return m
+}
@@ -0,0 +1,86 @@
+package board
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// the cell's coordinates as indices split by ., like a cell in Notation
+func pathString(size Size, path []*Coord) string {
+	nums := make([]string, len(path))
+	for i, c := range path {
+		idx, _ := size.Index(c)
+		nums[i] = size.cellString(1, idx)
+	}
+	return strings.Join(nums, ".")
+}
+
+func pathStrings(size Size, paths [][]*Coord) []string {
+	var strs []string
+	for _, path := range paths {
+		strs = append(strs, pathString(size, path))
+	}
+	return strs
+}
+
+// the threats as <cell>@<index of At>, with - for the board
+func threatStrings(size Size, threats []Threat) []string {
+	var strs []string
+	for _, t := range threats {
+		cell := "-"
+		if len(t.Cell) > 0 {
+			cell = pathString(size, t.Cell)
+		}
+		strs = append(strs, cell+"@"+pathString(size, []*Coord{t.At}))
+	}
+	return strs
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name, position, move string
+		captured, drawn      []string
+		result               Result
+		winner               Owner
+		// the cell the next move is forced into; empty if it's free
+		next    string
+		threats []string
+	}{
+		{"capture", "XX7/9/9/OO7/9/9/9/9/9 0 x", "0.2", []string{"0"}, nil, Result_ONGOING, Owner_NONE, "2", nil},
+		{"draw", "XOX6/XOO6/OX7/9/9/9/9/9/9 - x", "0.8", nil, []string{"0"}, Result_ONGOING, Owner_NONE, "8", nil},
+		{"free move", "XXX6/9/9/O8/O8/9/9/9/9 - o", "1.0", nil, nil, Result_ONGOING, Owner_NONE, "", nil},
+		{"threat", "X8/9/9/9/9/9/9/9/9 - x", "0.1", nil, nil, Result_ONGOING, Owner_NONE, "1", []string{"0@2"}},
+		{"board threat", "XXXXX4/9/9/9/9/9/9/9/9 1 x", "1.2", []string{"1"}, nil, Result_ONGOING, Owner_NONE, "2", []string{"-@2"}},
+		{"game over", "XXXXXXXX1/9/9/OO1OO1OO1/9/9/9/9/9 2 x", "2.2", []string{"2"}, nil, Result_PLAYER1_WIN, Owner_PLAYER1, "", nil},
+	}
+	for _, test := range tests {
+		p := mustParse(t, test.position)
+		size := p.Size()
+		m, err := size.ParseMove(test.move)
+		if err != nil {
+			t.Fatal(err)
+		}
+		player := p.Turn()
+		res := p.Describe(p.Apply(m))
+
+		if res.Player != player || res.Result != test.result || res.Winner != test.winner {
+			t.Errorf("%s: %v played with result %v %v, expected %v with %v %v", test.name, res.Player, res.Result, res.Winner, player, test.result, test.winner)
+		}
+		if captured := pathStrings(size, res.Captured); !reflect.DeepEqual(captured, test.captured) {
+			t.Errorf("%s: captured %v, expected %v", test.name, captured, test.captured)
+		}
+		if drawn := pathStrings(size, res.Drawn); !reflect.DeepEqual(drawn, test.drawn) {
+			t.Errorf("%s: drew %v, expected %v", test.name, drawn, test.drawn)
+		}
+		if next := pathString(size, res.Next); next != test.next {
+			t.Errorf("%s: next cell %q, expected %q", test.name, next, test.next)
+		}
+		if free := res.ToProto().Free; free != (test.next == "" && test.result == Result_ONGOING) {
+			t.Errorf("%s: free %v", test.name, free)
+		}
+		if threats := threatStrings(size, res.Threats); !reflect.DeepEqual(threats, test.threats) {
+			t.Errorf("%s: threats %v, expected %v", test.name, threats, test.threats)
+		}
+	}
+}
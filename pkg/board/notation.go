@@ -0,0 +1,339 @@
+package board
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ========== Notation ==========
+// A position can be written as a single line, like FEN in chess:
+//
+//	1X1O5/9/9/9/9/9/9/9/9 0 x
+//
+// The fields are split by spaces:
+//   - every row of spaces across the whole board, top to bottom and split
+//     by /, where X and O are claimed spaces and a number is that many
+//     empty spaces in a row
+//   - the cell the next move has to be made in as the indices of its
+//     coordinates, outermost first and split by ., or - if the move is free
+//   - whose turn it is, x or o
+//   - the rules, if they aren't the standard rules
+//   - the size as <rows>x<cols>, followed by k<in a row> and l<levels>
+//     where they aren't the default, if it isn't the standard size
+//   - the owners of cells that don't go to the player with the first
+//     line in them, as <cell>=<X or O> split by commas. Only cells where
+//     both players have a line, which the open cell rules allow, need it
+//
+// The last three can come in any order.
+
+// matches size tags, e.g. 4x4k3 or 3x3l3
+var sizeTag = regexp.MustCompile(`^(\d+)x(\d+)(?:k(\d+))?(?:l(\d+))?$`)
+
+// the size as <rows>x<cols>, followed by k<in a row> and l<levels>
+// where they aren't the default, e.g. 4x4k3
+func (s Size) Tag() string {
+	tag := fmt.Sprintf("%dx%d", s.Rows, s.Cols)
+	if s.InARow != minInt(s.Rows, s.Cols) {
+		tag += fmt.Sprintf("k%d", s.InARow)
+	}
+	if s.Levels != DEFAULT_LEVELS {
+		tag += fmt.Sprintf("l%d", s.Levels)
+	}
+	return tag
+}
+
+// reads a valid size in the format returned by Size.Tag
+func ParseSize(tag string) (Size, error) {
+	m := sizeTag.FindStringSubmatch(tag)
+	if m == nil {
+		return Size{}, fmt.Errorf("invalid size %q, expected <rows>x<cols>, optionally followed by k<in a row> and l<levels>", tag)
+	}
+	size := Size{Levels: DEFAULT_LEVELS}
+	size.Rows, _ = strconv.Atoi(m[1])
+	size.Cols, _ = strconv.Atoi(m[2])
+	size.InARow = minInt(size.Rows, size.Cols)
+	if m[3] != "" {
+		size.InARow, _ = strconv.Atoi(m[3])
+	}
+	if m[4] != "" {
+		size.Levels, _ = strconv.Atoi(m[4])
+	}
+	return size, size.Validate()
+}
+
+// the move as the indices of its coordinates, outermost first and
+// split by ., e.g. 4.0; the notation used by game records
+func (s Size) MoveString(m *Move) string {
+	idx, _ := s.MoveIndex(m)
+	return s.cellString(s.Levels, idx)
+}
+
+// reads a move in the format returned by Size.MoveString
+func (s Size) ParseMove(str string) (*Move, error) {
+	nums := strings.Split(str, ".")
+	if len(nums) != s.Levels {
+		return nil, fmt.Errorf("invalid move %q, expected %d indices split by .", str, s.Levels)
+	}
+	path := make([]*Coord, len(nums))
+	for i, num := range nums {
+		idx, err := strconv.ParseUint(num, 10, 32)
+		if err != nil || idx >= uint64(s.Cells()) {
+			return nil, fmt.Errorf("invalid move %q, expected indices from 0 to %d", str, s.Cells()-1)
+		}
+		path[i] = s.Coord(uint32(idx))
+	}
+	return NewMove(path...), nil
+}
+
+// Notation returns the position in the format read by ParsePosition
+func (p *Position) Notation() string {
+	size := p.size
+	height, width := size.Grid()
+	var sb strings.Builder
+	for row := 0; row < height; row++ {
+		if row > 0 {
+			sb.WriteByte('/')
+		}
+		empty := 0
+		for col := 0; col < width; col++ {
+			owner := p.childOwner(size.Levels, size.GridIndex(row, col))
+			if owner == Owner_NONE {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteString(ownerMark(owner))
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+	}
+
+	sb.WriteByte(' ')
+	if p.targetLevel == 0 {
+		sb.WriteByte('-')
+	} else {
+		sb.WriteString(size.cellString(p.targetLevel, p.target))
+	}
+
+	sb.WriteByte(' ')
+	sb.WriteString(strings.ToLower(ownerMark(p.turn)))
+	if p.rules.Name() != (StandardRules{}).Name() {
+		sb.WriteString(" " + p.rules.Name())
+	}
+	if size != DefaultSize() {
+		sb.WriteString(" " + size.Tag())
+	}
+
+	var owners []string
+	for level := 1; level < size.Levels; level++ {
+		for idx := uint32(0); idx < p.counts[level]; idx++ {
+			nd, owner := p.node(level, idx), p.childOwner(level, idx)
+			if owner != p.g.lineOwner(nd.owned[0], nd.owned[1]) {
+				owners = append(owners, size.cellString(level, idx)+"="+ownerMark(owner))
+			}
+		}
+	}
+	if len(owners) > 0 {
+		sb.WriteString(" " + strings.Join(owners, ","))
+	}
+	return sb.String()
+}
+
+// X for PLAYER1 and O for PLAYER2
+func ownerMark(o Owner) string {
+	if o == Owner_PLAYER2 {
+		return "O"
+	}
+	return "X"
+}
+
+// ParsePosition reads a position written in the format returned by
+// Notation. It only checks the position's shape; use Validate to check
+// that it could occur, or ParseValidPosition to do both. If the cell the next move has
+// to be made in is closed, it can be made in the cells holding it instead
+func ParsePosition(s string) (*Position, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 3 || len(fields) > 6 {
+		return nil, fmt.Errorf("invalid position %q, expected <spaces> <cell> <turn> [rules] [size] [owners]", s)
+	}
+
+	var r Rules = StandardRules{}
+	size := DefaultSize()
+	owners := ""
+	// each tag can only be given once
+	seen := map[string]bool{}
+	for _, tag := range fields[3:] {
+		kind := "rules"
+		switch {
+		case strings.Contains(tag, "="):
+			kind = "owners"
+		case sizeTag.MatchString(tag):
+			kind = "size"
+		}
+		if seen[kind] {
+			return nil, fmt.Errorf("invalid position %q, %s given more than once", s, kind)
+		}
+		seen[kind] = true
+
+		var err error
+		switch kind {
+		case "owners":
+			owners = tag
+		case "size":
+			size, err = ParseSize(tag)
+		default:
+			r, err = RulesByName(tag)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := size.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &Position{rules: r}
+	p.init(size)
+	if err := p.parseSpaces(fields[0]); err != nil {
+		return nil, err
+	}
+	// owners have to be in place before the cells are updated,
+	// which would give them to the first line
+	if err := p.parseOwners(owners); err != nil {
+		return nil, err
+	}
+	for level := size.Levels - 1; level > 0; level-- {
+		for idx := uint32(0); idx < p.counts[level]; idx++ {
+			p.update(level, idx, r)
+		}
+	}
+
+	if fields[1] != "-" {
+		var err error
+		if p.targetLevel, p.target, err = size.parseCell(fields[1]); err != nil {
+			return nil, err
+		}
+		p.staleTarget = !p.open(p.targetLevel, p.target)
+		p.openTarget()
+	}
+
+	switch fields[2] {
+	case "x":
+		p.turn = Owner_PLAYER1
+	case "o":
+		p.turn = Owner_PLAYER2
+	default:
+		return nil, fmt.Errorf("invalid turn %q, expected x or o", fields[2])
+	}
+	p.key = p.computeKey()
+	return p, nil
+}
+
+// the indices of the coordinates of the cell with the given level and
+// index, outermost first and split by .
+func (s Size) cellString(level int, idx uint32) string {
+	nums := make([]string, level)
+	for i := level - 1; i >= 0; i-- {
+		nums[i] = strconv.Itoa(int(idx % uint32(s.Cells())))
+		idx /= uint32(s.Cells())
+	}
+	return strings.Join(nums, ".")
+}
+
+// reads the indices of a cell's coordinates, outermost first and split by .
+func (s Size) parseCell(field string) (level int, idx uint32, err error) {
+	nums := strings.Split(field, ".")
+	if len(nums) >= s.Levels {
+		return 0, 0, fmt.Errorf("invalid cell %q, expected at most %d indices", field, s.Levels-1)
+	}
+	for _, num := range nums {
+		i, err := strconv.ParseUint(num, 10, 32)
+		if err != nil || i >= uint64(s.Cells()) {
+			return 0, 0, fmt.Errorf("invalid cell %q, expected indices from 0 to %d split by .", field, s.Cells()-1)
+		}
+		idx = idx*uint32(s.Cells()) + uint32(i)
+	}
+	return len(nums), idx, nil
+}
+
+// gives cells the owners in the owners field of a position
+func (p *Position) parseOwners(field string) error {
+	if field == "" {
+		return nil
+	}
+	n := uint32(p.g.n)
+	for _, owner := range strings.Split(field, ",") {
+		cell, mark, _ := strings.Cut(owner, "=")
+		level, idx, err := p.size.parseCell(cell)
+		if err != nil {
+			return err
+		}
+		player := 0
+		switch mark {
+		case "X":
+		case "O":
+			player = 1
+		default:
+			return fmt.Errorf("invalid owner %q, expected <cell>=X or <cell>=O", owner)
+		}
+		parent := p.node(level-1, idx/n)
+		parent.owned[player] |= 1 << (idx % n)
+		parent.owned[1-player] &^= 1 << (idx % n)
+	}
+	return nil
+}
+
+// claims the spaces given by the first field of a position
+func (p *Position) parseSpaces(field string) error {
+	size := p.size
+	height, width := size.Grid()
+	rows := strings.Split(field, "/")
+	if len(rows) != height {
+		return fmt.Errorf("expected %d rows of spaces, got %d", height, len(rows))
+	}
+
+	n := uint32(p.g.n)
+	for row, line := range rows {
+		col := 0
+		for i := 0; i < len(line); i++ {
+			player := 0
+			switch c := line[i]; {
+			case c >= '0' && c <= '9':
+				j := i
+				for j < len(line) && line[j] >= '0' && line[j] <= '9' {
+					j++
+				}
+				empty, err := strconv.Atoi(line[i:j])
+				if err != nil || empty > width-col {
+					return fmt.Errorf("expected %d spaces in row %d, got more", width, row+1)
+				}
+				col += empty
+				i = j - 1
+				continue
+			case c == 'X':
+			case c == 'O':
+				player = 1
+			default:
+				return fmt.Errorf("invalid space %q in row %d, expected X, O or a number", c, row+1)
+			}
+			if col >= width {
+				return fmt.Errorf("expected %d spaces in row %d, got more", width, row+1)
+			}
+			space := size.GridIndex(row, col)
+			nd, bit := p.node(size.Levels-1, space/n), uint64(1)<<(space%n)
+			nd.owned[player] |= bit
+			nd.closed |= bit
+			col++
+		}
+		if col != width {
+			return fmt.Errorf("expected %d spaces in row %d, got %d", width, row+1, col)
+		}
+	}
+	return nil
+}
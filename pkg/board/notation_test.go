@@ -0,0 +1,42 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+// positions read back from their notation have the same cells, next
+// cell, turn and rules as the positions that wrote them
+func TestNotationRoundTrip(t *testing.T) {
+	playRandomGames(t, 5, func(t *testing.T, p *Position) {
+		s := p.Notation()
+		q, err := ParsePosition(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if q.Size() != p.Size() || q.Rules().Name() != p.Rules().Name() || q.Turn() != p.Turn() {
+			t.Fatalf("%s: read back as %v, %s rules, %v to move", s, q.Size(), q.Rules().Name(), q.Turn())
+		}
+		if !reflect.DeepEqual(q.nodes, p.nodes) {
+			t.Fatalf("%s: read back with other cell owners as %s", s, q.Notation())
+		}
+		if q.targetLevel != p.targetLevel || q.target != p.target {
+			t.Fatalf("%s: read back with the next cell at level %d index %d, expected level %d index %d", s, q.targetLevel, q.target, p.targetLevel, p.target)
+		}
+		if q.Key() != p.Key() || q.Notation() != s {
+			t.Fatalf("%s: read back with key %x as %s, expected key %x", s, q.Key(), q.Notation(), p.Key())
+		}
+	})
+}
+
+func TestParsePositionTwice(t *testing.T) {
+	for _, s := range []string{
+		"9/9/9/9/9/9/9/9/9 - x open misere",
+		"9/9/9/9/9/9/9/9/9 - x 3x3 3x3",
+		"9/9/9/9/9/9/9/9/9 - x open 0=X 1=O",
+	} {
+		if _, err := ParsePosition(s); err == nil {
+			t.Errorf("%s: read without an error", s)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package board
+
+// ========== Observations ==========
+// An observation is a position laid out as the input of the models in
+// py/, the same way UltimateTicTacToeEnv._process_state lays it out:
+// for every deepest cell (the ones holding spaces), in row-major order
+// level by level, and every space in it, 4 features:
+//
+//	0: who claimed the space (0, 1 or 2, see Owner)
+//	1: who won the outermost cell holding it
+//	2: 1 if the next move has to be made in the space's cell, else 0.
+//	   When it can be made anywhere, every cell with a legal move counts
+//	3: whose turn it is (1 or 2)
+
+// the number of features of every space in an observation
+const OBS_FEATURES = 4
+
+// the shape of an observation of a position of this size: the deepest
+// cells, the spaces in each and the features of each space
+func (s Size) ObservationShape() []int {
+	return []int{s.Spaces() / s.Cells(), s.Cells(), OBS_FEATURES}
+}
+
+// Observation returns the position as an observation, flattened in
+// row-major order; see Size.ObservationShape for its shape
+func (p *Position) Observation() []float32 {
+	n := uint32(p.g.n)
+	deepest := p.size.Levels - 1
+	obs := make([]float32, int(p.counts[p.size.Levels])*OBS_FEATURES)
+
+	// the deepest cells the next move has to be made in
+	cur := make([]bool, p.counts[deepest])
+	if p.targetLevel > 0 {
+		width := p.counts[deepest] / p.counts[p.targetLevel]
+		for i := p.target * width; i < (p.target+1)*width; i++ {
+			cur[i] = true
+		}
+	} else if result, _ := p.Result(); result == Result_ONGOING {
+		for _, space := range p.moves(nil) {
+			cur[space/n] = true
+		}
+	}
+
+	// the number of deepest cells in each outermost cell
+	perCell := p.counts[deepest] / n
+	for space := uint32(0); space < p.counts[p.size.Levels]; space++ {
+		cell := space / n
+		f := obs[space*OBS_FEATURES : (space+1)*OBS_FEATURES]
+		f[0] = float32(p.childOwner(p.size.Levels, space))
+		f[1] = float32(p.cellOwner(int(cell / perCell)))
+		if cur[cell] {
+			f[2] = 1
+		}
+		f[3] = float32(p.turn)
+	}
+	return obs
+}
+
+// LegalMask returns whether or not a move can be made in each space,
+// by its index among all the spaces of the board (see Size.MoveIndex).
+// Nothing can be played once the game is over
+func (p *Position) LegalMask() []bool {
+	mask := make([]bool, p.counts[p.size.Levels])
+	if result, _ := p.Result(); result != Result_ONGOING {
+		return mask
+	}
+	for _, space := range p.moves(nil) {
+		mask[space] = true
+	}
+	return mask
+}
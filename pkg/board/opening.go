@@ -0,0 +1,68 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ========== Opening ==========
+
+// Opening decides where the first move of a game can be made.
+// It's either the index of the cell the first move is forced into,
+// or one of FreeOpening and CenterOpening
+type Opening int
+
+const (
+	// the standard opening; the first move can be made anywhere
+	FreeOpening Opening = -1
+	// forces the first move into the center cell. On boards with an
+	// even number of rows or columns it's the lower or right one of
+	// the two middle cells, e.g. row 2 of rows 0 to 3
+	CenterOpening Opening = -2
+)
+
+// forces the first move into the cell with the given index
+func CellOpening(idx uint32) Opening {
+	return Opening(idx)
+}
+
+// parses an opening for a board of the given size: "free", "center",
+// or the index of a cell
+func ParseOpening(s string, size Size) (Opening, error) {
+	switch s {
+	case "free":
+		return FreeOpening, nil
+	case "center":
+		return CenterOpening, nil
+	}
+	idx, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || idx >= uint64(size.Cells()) {
+		return FreeOpening, fmt.Errorf("invalid opening %q, expected free, center or a cell from 0 to %d", s, size.Cells()-1)
+	}
+	return CellOpening(uint32(idx)), nil
+}
+
+// the opening in the format read by ParseOpening
+func (o Opening) String() string {
+	switch o {
+	case FreeOpening:
+		return "free"
+	case CenterOpening:
+		return "center"
+	}
+	return strconv.Itoa(int(o))
+}
+
+// returns an empty position of the given valid size under the rules
+// whose first move follows the opening
+func (o Opening) NewPosition(size Size, r Rules) *Position {
+	p := NewPosition(size, r)
+	switch {
+	case o == CenterOpening:
+		p.targetLevel, p.target = 1, uint32(size.Rows/2*size.Cols+size.Cols/2)
+	case o >= 0:
+		p.targetLevel, p.target = 1, uint32(o)
+	}
+	p.key = p.computeKey()
+	return p
+}
@@ -0,0 +1,63 @@
+package board
+
+// ========== Perft ==========
+// Perft walks the game tree to count positions. The counts only depend
+// on the rules, so they can be checked against published numbers and
+// against other move generators.
+
+// Perft returns the number of positions reached by playing every legal
+// sequence of depth moves from the position, where games that are over
+// have no moves. The position is left as it was
+func Perft(p *Position, depth int) uint64 {
+	if depth <= 0 {
+		return 1
+	}
+	buf := make([][]uint32, depth)
+	return perft(p, buf, depth)
+}
+
+// buf holds a slice of moves to reuse for each depth
+func perft(p *Position, buf [][]uint32, depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+	if result, _ := p.Result(); result != Result_ONGOING {
+		return 0
+	}
+	moves := p.moves(buf[depth-1][:0])
+	buf[depth-1] = moves
+	if depth == 1 {
+		return uint64(len(moves))
+	}
+
+	var nodes uint64
+	for _, space := range moves {
+		rec := p.apply(space)
+		nodes += perft(p, buf, depth-1)
+		p.Undo(rec)
+	}
+	return nodes
+}
+
+// MoveCount is the perft count below a single move
+type MoveCount struct {
+	Move  *Move
+	Count uint64
+}
+
+// Divide returns the perft count of depth - 1 after each legal move,
+// in the order of Moves. They add up to the position's perft count
+func Divide(p *Position, depth int) []MoveCount {
+	if depth < 1 {
+		return nil
+	}
+	moves := p.Moves()
+	buf := make([][]uint32, depth)
+	counts := make([]MoveCount, len(moves))
+	for i, m := range moves {
+		rec := p.Apply(m)
+		counts[i] = MoveCount{Move: m, Count: perft(p, buf, depth-1)}
+		p.Undo(rec)
+	}
+	return counts
+}
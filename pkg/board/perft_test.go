@@ -0,0 +1,30 @@
+package board
+
+import "testing"
+
+// the published counts from the standard opening, by depth
+var perftCounts = []uint64{1, 81, 720, 6336, 55080}
+
+func TestPerft(t *testing.T) {
+	p := NewPosition(DefaultSize(), StandardRules{})
+	before := p.Notation()
+	for depth, want := range perftCounts {
+		if got := Perft(p, depth); got != want {
+			t.Errorf("perft %d: got %d, expected %d", depth, got, want)
+		}
+	}
+	if p.Notation() != before || p.Key() != p.computeKey() {
+		t.Errorf("perft left the position at %s", p.Notation())
+	}
+}
+
+func TestDivide(t *testing.T) {
+	p := NewPosition(DefaultSize(), StandardRules{})
+	var total uint64
+	for _, mc := range Divide(p, 4) {
+		total += mc.Count
+	}
+	if total != perftCounts[4] {
+		t.Errorf("divide 4 adds up to %d, expected %d", total, perftCounts[4])
+	}
+}
@@ -0,0 +1,327 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+	"uttt/pkg/color"
+)
+
+// ========== Position ==========
+// A Position is the state of a game between moves under some rules:
+// who claimed every space, who won every cell, where the next move
+// has to be made and whose turn it is. Cell owners, closed cells and
+// the zobrist key are kept up to date as moves are made instead of
+// being recomputed. Game logic works on positions; the protobuf
+// Board is only for sending them to clients, see FromProto and ToProto.
+
+type Position struct {
+	bitboard
+	rules Rules
+	turn  Owner
+	key   uint64
+
+	// whether the board the position was loaded from pointed the next
+	// move at a closed cell; see Validate
+	staleTarget bool
+}
+
+// returns an empty position of the given valid size where PLAYER1 can
+// make the first move in any cell; see Opening.NewPosition for other openings
+func NewPosition(size Size, r Rules) *Position {
+	p := &Position{rules: r, turn: Owner_PLAYER1}
+	p.init(size)
+	p.key = p.computeKey()
+	return p
+}
+
+// FromProto converts a board received from a client to a position under
+// the rules. Boards from clients can be anything, so the board is
+// rejected if it doesn't have the size's shape or, with an error from
+// Check, if the position couldn't come up in a game. It's PLAYER1's
+// turn if both players claimed the same number of spaces, otherwise PLAYER2's
+func FromProto(b *Board, r Rules) (*Position, error) {
+	size := b.Size()
+	if err := size.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkShape(size, b.Cells, size.Levels-1); err != nil {
+		return nil, err
+	}
+
+	p := &Position{rules: r, turn: Owner_PLAYER1}
+	p.load(b, r)
+	level, _ := size.target(b.CurCell, b.CurCells)
+	p.staleTarget = level != p.targetLevel
+	if p.count(0) != p.count(1) {
+		p.turn = Owner_PLAYER2
+	}
+	p.key = p.computeKey()
+	if err := Check(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// returns an error if the cells don't have the shape of the size,
+// where levels is the number of levels below them
+func checkShape(size Size, cells []*Cell, levels int) error {
+	if len(cells) != size.Cells() {
+		return fmt.Errorf("expected %d cells, got %d", size.Cells(), len(cells))
+	}
+	for _, cell := range cells {
+		if levels == 1 {
+			if len(cell.Spaces) != size.Cells() || len(cell.Cells) != 0 {
+				return fmt.Errorf("expected cells of %d spaces", size.Cells())
+			}
+			continue
+		}
+		if len(cell.Spaces) != 0 {
+			return fmt.Errorf("expected cells of %d cells, got spaces", size.Cells())
+		}
+		if err := checkShape(size, cell.Cells, levels-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToProto converts the position to a board to send to clients.
+// Every won cell gets its winner, and curCell is invalid when the
+// next move can be made in any cell
+func (p *Position) ToProto() *Board {
+	b := &Board{Cells: p.protoCells(1, 0), CurCell: &Coord{}, Rows: int32(p.size.Rows), Cols: int32(p.size.Cols), Inarow: int32(p.size.InARow), Levels: int32(p.size.Levels)}
+	b.CurCell.Invalidate()
+	if p.targetLevel > 0 {
+		path := p.Target()
+		b.CurCell = path[0]
+		if len(path) > 1 {
+			b.CurCells = path[1:]
+		}
+	}
+	return b
+}
+
+// the cells held by the node with the given index at the level above
+func (p *Position) protoCells(level int, parent uint32) []*Cell {
+	n := uint32(p.g.n)
+	cells := make([]*Cell, n)
+	for i := range cells {
+		idx := parent*n + uint32(i)
+		cell := &Cell{Winner: p.childOwner(level, idx)}
+		if level == p.size.Levels-1 {
+			cell.Spaces = make([]*Space, n)
+			for j := range cell.Spaces {
+				cell.Spaces[j] = &Space{Val: p.childOwner(level+1, idx*n+uint32(j))}
+			}
+		} else {
+			cell.Cells = p.protoCells(level+1, idx)
+		}
+		cells[i] = cell
+	}
+	return cells
+}
+
+// returns a copy of the position that can be changed independently
+func (p *Position) Clone() *Position {
+	c := *p
+	c.nodes = append([]node(nil), p.nodes...)
+	return &c
+}
+
+func (p *Position) Size() Size {
+	return p.size
+}
+func (p *Position) Rules() Rules {
+	return p.rules
+}
+
+// whose turn it is
+func (p *Position) Turn() Owner {
+	return p.turn
+}
+
+// the result of the game under the position's rules and who won, if anyone
+func (p *Position) Result() (Result, Owner) {
+	return p.rules.Result(p)
+}
+
+// who has a line of cells on the board, if anyone.
+// Whether that wins the game is up to the rules; see Result
+func (p *Position) Owner() Owner {
+	return p.owner()
+}
+
+// whether or not no cell can be played in anymore
+func (p *Position) Full() bool {
+	return p.allClosed()
+}
+
+// the number of spaces claimed by the player
+func (p *Position) Count(player Owner) int {
+	if pi, valid := playerIndex(player); valid {
+		return p.count(pi)
+	}
+	return 0
+}
+
+// the level and index of the cell at the end of the path of coordinates,
+// outermost first
+func (p *Position) cellIndex(path []*Coord) (level int, idx uint32, valid bool) {
+	if len(path) == 0 || len(path) >= p.size.Levels {
+		return 0, 0, false
+	}
+	idx, valid = p.size.PathIndex(path)
+	return len(path), idx, valid
+}
+
+// who won the cell at the end of the path of coordinates, outermost first
+func (p *Position) CellOwner(path ...*Coord) Owner {
+	level, idx, valid := p.cellIndex(path)
+	if !valid {
+		return Owner_NONE
+	}
+	return p.childOwner(level, idx)
+}
+
+// the owner of every outermost cell, in row-major order
+func (p *Position) CellOwners() []Owner {
+	owners := make([]Owner, p.g.n)
+	for i := range owners {
+		owners[i] = p.cellOwner(i)
+	}
+	return owners
+}
+
+// who claimed the space the move would claim
+func (p *Position) SpaceOwner(m *Move) Owner {
+	idx, valid := p.size.MoveIndex(m)
+	if !valid {
+		return Owner_NONE
+	}
+	return p.childOwner(p.size.Levels, idx)
+}
+
+// whether or not moves can still be made in the cell at the end of the
+// path of coordinates, outermost first. Every cell along the way has
+// to be open too
+func (p *Position) Open(path ...*Coord) bool {
+	level, idx, valid := p.cellIndex(path)
+	return valid && p.open(level, idx)
+}
+
+// the coordinates of the cell the next move has to be made in,
+// outermost first; nil if it can be made in any cell
+func (p *Position) Target() []*Coord {
+	if p.targetLevel == 0 {
+		return nil
+	}
+	return p.size.Path(p.target, p.targetLevel)
+}
+
+// whether or not the move can be made. No moves can be made once the game is over
+func (p *Position) Legal(m *Move) bool {
+	idx, valid := p.size.MoveIndex(m)
+	if !valid {
+		return false
+	}
+	if result, _ := p.Result(); result != Result_ONGOING {
+		return false
+	}
+	return p.legal(idx)
+}
+
+// every move that can be made, in row-major order level by level.
+// There are none once the game is over
+func (p *Position) Moves() []*Move {
+	if result, _ := p.Result(); result != Result_ONGOING {
+		return nil
+	}
+	idxs := p.moves(nil)
+	moves := make([]*Move, len(idxs))
+	for i, idx := range idxs {
+		moves[i] = p.size.Move(idx)
+	}
+	return moves
+}
+
+// Returns a string printable to color-supporting terminals.
+// Outermost cells are split by | and lines of -, and on positions with
+// more than two levels the cells inside them by : and lines of .
+func (p *Position) TerminalString() string {
+	return p.terminalString(-1)
+}
+
+// TerminalStringMove is TerminalString with the space the move
+// claimed highlighted, e.g. to show the last move
+func (p *Position) TerminalStringMove(m *Move) string {
+	idx, valid := p.size.MoveIndex(m)
+	if !valid {
+		return p.terminalString(-1)
+	}
+	return p.terminalString(int64(idx))
+}
+
+// renders the position, highlighting the space with the given index
+// unless it's negative
+func (p *Position) terminalString(highlight int64) string {
+	size := p.size
+	n := uint32(size.Cells())
+
+	// the number of rows and columns of spaces across a cell
+	// at each level, where level 0 is the whole board
+	heights, widths := make([]int, size.Levels+1), make([]int, size.Levels+1)
+	heights[size.Levels], widths[size.Levels] = 1, 1
+	for level := size.Levels - 1; level >= 0; level-- {
+		heights[level], widths[level] = heights[level+1]*size.Rows, widths[level+1]*size.Cols
+	}
+	line := widths[0] / size.Cols * (2*size.Cols + 2)
+
+	ret := ""
+	for row := 0; row < heights[0]; row++ {
+		for col := 0; col < widths[0]; col += size.Cols {
+			// the index of the deepest cell this row of spaces is in
+			var cell uint32
+			for level := 1; level < size.Levels; level++ {
+				cell = cell*n + uint32(row/heights[level]%size.Rows*size.Cols+col/widths[level]%size.Cols)
+			}
+			rowColor := color.Reset
+			if p.targetLevel > 0 && cell/p.counts[size.Levels-1-p.targetLevel] == p.target {
+				rowColor = color.Red
+				ret += rowColor
+			}
+			innerRow := row % size.Rows
+			for innerCol := 0; innerCol < size.Cols; innerCol++ {
+				space := cell*n + uint32(innerRow*size.Cols+innerCol)
+				if int64(space) == highlight {
+					ret += color.Yellow
+				}
+				switch p.childOwner(size.Levels, space) {
+				case 1:
+					ret += "X "
+				case 2:
+					ret += "O "
+				default:
+					ret += "_ "
+				}
+				if int64(space) == highlight {
+					ret += rowColor
+				}
+			}
+			ret += color.Reset
+			if (col+size.Cols)%widths[1] == 0 {
+				ret += "| "
+			} else {
+				ret += ": "
+			}
+		}
+		ret += "\n"
+		if (row+1)%heights[1] == 0 {
+			ret += strings.Repeat("-", line) + "\n"
+		} else if (row+1)%size.Rows == 0 {
+			ret += strings.Repeat(".", line) + "\n"
+		}
+	}
+
+	ret += "\n"
+	return ret
+}
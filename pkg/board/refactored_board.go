@@ -1,133 +1,49 @@
 package board
 
-import (
-	"uttt/pkg/color"
-)
-
-// ========== abstractions ==========
+// ========== Coord Methods ==========
 
-type abstractProtoSpace interface {
-	Owner() Owner
+// whether or not the coordinate points anywhere. It is invalid if either Col or Row
+// is negative; use Size.Contains to check that it's on a board of a given size
+func (c *Coord) Valid() bool {
+	return c != nil && c.Col >= 0 && c.Row >= 0
 }
 
-type abstractProtoCell interface {
-	Get(c *Coord) abstractProtoSpace
-	Owner() Owner
-	Full() bool
+func (c *Coord) Invalidate() {
+	c.Col, c.Row = -1, -1
 }
 
-// ========== abstract functions ==========
-
-// This returns who the Owner of the  abstractProtoCell is.
-// It checks diagonals as well as rows and columns
-func getOwner(ac abstractProtoCell) Owner {
-	c := Coord{}
-	prev := Coord{}
-
-	// check if any of the rows are claimed
-	for row := 0; row < ROWS; row++ {
-		allSame := true
-		for col := 1; col < COLS; col++ {
-			c.Row, c.Col = int32(row), int32(col)
-			prev.Row, prev.Col = int32(row), int32(col-1)
-			if ac.Get(&c).Owner() != ac.Get(&prev).Owner() {
-				allSame = false
-				break
-			}
-		}
-		if allSame && ac.Get(&c).Owner() != Owner_NONE {
-			return ac.Get(&c).Owner()
-		}
-	}
+// ========== Move Methods ==========
 
-	// check if any of the columns have been claimed
-	for col := 0; col < COLS; col++ {
-		allSame := true
-		for row := 1; row < ROWS; row++ {
-			c.Row, c.Col = int32(row), int32(col)
-			prev.Row, prev.Col = int32(row-1), int32(col)
-			if ac.Get(&c).Owner() != ac.Get(&prev).Owner() {
-				allSame = false
-				break
-			}
-		}
-		if allSame && ac.Get(&c).Owner() != Owner_NONE {
-			return ac.Get(&c).Owner()
-		}
+// makes a move from the coordinates of the cells it's in, outermost
+// first, followed by the coordinate of its space
+func NewMove(path ...*Coord) *Move {
+	m := &Move{Large: path[0], Small: path[len(path)-1]}
+	if len(path) > 2 {
+		m.Mid = path[1 : len(path)-1]
 	}
-
-	// check the left diagonal
-	allSameLeft := true
-	for i := 1; i < 3; i++ {
-		c.Row, c.Col = int32(i), int32(i)
-		prev.Row, prev.Col = int32(i-1), int32(i-1)
-		if ac.Get(&c).Owner() != ac.Get(&prev).Owner() {
-			allSameLeft = false
-			break
-		}
-	}
-	if allSameLeft && ac.Get(&c).Owner() != Owner_NONE {
-		return ac.Get(&c).Owner()
-	}
-
-	// check right diagonal
-	allSameRight := true
-	for row, col := 1, 1; row < 3; row, col = row+1, col-1 {
-		c.Row, c.Col = int32(row), int32(col)
-		prev.Row, prev.Col = int32(row-1), int32(col+1)
-		if ac.Get(&c).Owner() != ac.Get(&prev).Owner() {
-			allSameRight = false
-			break
-		}
-	}
-	if allSameRight && ac.Get(&c).Owner() != Owner_NONE {
-		return ac.Get(&c).Owner()
-	}
-
-	// no one is the Owner
-	return 0
+	return m
 }
 
-// An abstractProtoCell is full if all of the spaces
-// within it have an Owner
-func isFull(ac abstractProtoCell) bool {
-	c := Coord{}
-	for row := 0; row < 3; row++ {
-		for col := 0; col < 3; col++ {
-			c.Row, c.Col = int32(row), int32(col)
-			if ac.Get(&c).Owner() == Owner_NONE {
-				return false
-			}
-		}
-	}
-	return true
+// the coordinates of the cells the move is in, outermost first,
+// followed by the coordinate of its space
+func (m *Move) Path() []*Coord {
+	path := make([]*Coord, 0, len(m.GetMid())+2)
+	path = append(path, m.GetLarge())
+	path = append(path, m.GetMid()...)
+	return append(path, m.GetSmall())
 }
 
-// ========== Coord Methods ==========
+// ========== Owner Methods ==========
 
-// converts the coordinate to a 1D array index
-func (c *Coord) Index() (idx uint32, valid bool) {
-	if !c.Valid() {
-		valid = false
-		return
+// the other player; NONE stays NONE
+func (o Owner) Opponent() Owner {
+	switch o {
+	case Owner_PLAYER1:
+		return Owner_PLAYER2
+	case Owner_PLAYER2:
+		return Owner_PLAYER1
 	}
-	idx = uint32(c.Col + c.Row*COLS)
-	return
-}
-
-// converts a 1D array index to a coordinate
-func ToCoord(idx uint32) *Coord {
-	return &Coord{Row: int32(idx / COLS), Col: int32(idx % COLS)}
-}
-
-// whether or not the coordinate is valid. It is invalid if either Col or Row is negative
-// or if either Col or Row is greater than the max number of columns or rows, respectively
-func (c *Coord) Valid() bool {
-	return (c.Col >= 0 && c.Row >= 0) && (c.Col < COLS && c.Row < ROWS)
-}
-
-func (c *Coord) Invalidate() {
-	c.Col, c.Row = -1, -1
+	return Owner_NONE
 }
 
 // ========== Space Methods ==========
@@ -139,115 +55,70 @@ func (s *Space) Owner() Owner {
 }
 
 // ========== Cell Methods ==========
-func NewProtoCell() *Cell {
-	spaces := make([]*Space, CELLS)
-	for i := 0; i < CELLS; i++ {
-		spaces[i] = NewProtoSpace()
-	}
-	return &Cell{Spaces: spaces}
-}
 
-// Returns the corresponding *Space
-func (ce *Cell) Get(co *Coord) abstractProtoSpace {
-	idx, _ := co.Index()
-	return ce.Spaces[idx]
-}
-func (c *Cell) Full() bool {
-	return isFull(c)
-}
-func (c *Cell) Owner() Owner {
-	return getOwner(c)
+// returns an empty outermost cell of a board of the given size
+func NewProtoCell(size Size) *Cell {
+	return newProtoCell(size, size.Levels-1)
 }
 
-// ========== Board Methods ==========
-func NewProtoBoard() *Board {
-	cells := make([]*Cell, CELLS)
-	for i := 0; i < CELLS; i++ {
-		cells[i] = NewProtoCell()
+// returns an empty cell with the given number of levels of cells
+// below it, the last of which holds spaces
+func newProtoCell(size Size, levels int) *Cell {
+	if levels <= 1 {
+		spaces := make([]*Space, size.Cells())
+		for i := range spaces {
+			spaces[i] = NewProtoSpace()
+		}
+		return &Cell{Spaces: spaces}
 	}
-	return &Board{Cells: cells, CurCell: &Coord{Row: ROWS / 2, Col: COLS / 2}}
-}
-
-// returns the corresponding *Coord
-func (b *Board) Get(c *Coord) abstractProtoSpace {
-	idx, _ := c.Index()
-	return b.Cells[idx]
-}
-
-// get the space at b.Cells[outer].Spaces[inner]
-func (b *Board) get(outer, inner int) *Space {
-	return b.Cells[outer].Spaces[inner]
+	cells := make([]*Cell, size.Cells())
+	for i := range cells {
+		cells[i] = newProtoCell(size, levels-1)
+	}
+	return &Cell{Cells: cells}
 }
 
-func (b *Board) Full() bool {
-	for i := 0; i < CELLS; i++ {
-		// if any of the cells aren't full and have no owner
-		// then the board isn't full
-		if !b.Get(ToCoord(uint32(i))).(*Cell).Full() && b.Get(ToCoord(uint32(i))).Owner() == Owner_NONE {
+// whether or not every space in the cell is claimed
+func (c *Cell) Full() bool {
+	for _, sub := range c.Cells {
+		if !sub.Full() {
+			return false
+		}
+	}
+	for _, s := range c.Spaces {
+		if s.Val == Owner_NONE {
 			return false
 		}
 	}
 	return true
 }
-func (b *Board) Owner() Owner {
-	return getOwner(b)
-}
-func (b *Board) Moves() []*Move {
-	moves := make([]*Move, 0)
-
-	if b.CurCell.Valid() {
-		// if there's a current cell,
-		// only get moves from that current cell
-		for inner := 0; inner < CELLS; inner++ {
-			if b.Get(b.CurCell).(*Cell).Get(ToCoord(uint32(inner))).Owner() == Owner_NONE {
-				moves = append(moves, &Move{Large: b.CurCell, Small: ToCoord(uint32(inner))})
-			}
-		}
-	} else {
-		// if there isn't a current cell, get all possible moves
-		// from the board
-		for outer := 0; outer < CELLS; outer++ {
-			if b.Get(ToCoord(uint32(outer))).Owner() != Owner_NONE {
-				continue
-			}
-			for inner := 0; inner < CELLS; inner++ {
-				if b.get(outer, inner).Owner() == Owner_NONE {
-					moves = append(moves, &Move{Large: ToCoord(uint32(outer)), Small: ToCoord(uint32(inner))})
-				}
-			}
-		}
+
+// ========== Board Methods ==========
+
+// returns an empty board of the given size where the first move can be
+// made in any cell. Games are played on Positions; see Position.ToProto
+func NewProtoBoard(size Size) *Board {
+	cells := make([]*Cell, size.Cells())
+	for i := range cells {
+		cells[i] = NewProtoCell(size)
 	}
+	curCell := &Coord{}
+	curCell.Invalidate()
+	return &Board{Cells: cells, CurCell: curCell, Rows: int32(size.Rows), Cols: int32(size.Cols), Inarow: int32(size.InARow), Levels: int32(size.Levels)}
+}
 
-	return moves
+// the size of the board. Fields that aren't set are those of the
+// standard game, and InARow defaults to the shorter side
+func (b *Board) Size() Size {
+	return protoSize(b.GetRows(), b.GetCols(), b.GetInarow(), b.GetLevels())
 }
 
-// Returns a string printable to color-supporting terminals
-func (b *Board) TerminalString() string {
-	ret := ""
-	for row := 0; row < ROWS; row++ {
-		for innerRow := 0; innerRow < ROWS; innerRow++ {
-			for col := 0; col < COLS; col++ {
-				if col == int(b.CurCell.Col) && row == int(b.CurCell.Row) {
-					ret += color.Red
-				}
-				for innerCol := 0; innerCol < COLS; innerCol++ {
-					switch b.get(row*ROWS+col, innerRow*ROWS+innerCol).Owner() {
-					case 1:
-						ret += "X "
-					case 2:
-						ret += "O "
-					default:
-						ret += "_ "
-					}
-				}
-				ret += color.Reset
-				ret += "| "
-			}
-			ret += "\n"
-		}
-		ret += "------------------------\n"
-	}
+// the size of the recorded game, where 0 means the default like for a Board
+func (g *GameRecord) Size() Size {
+	return protoSize(g.GetRows(), g.GetCols(), g.GetInarow(), g.GetLevels())
+}
 
-	ret += "\n"
-	return ret
+// a size from the fields of a message, filling in the defaults for 0
+func protoSize(rows, cols, inarow, levels int32) Size {
+	return Size{Rows: int(rows), Cols: int(cols), InARow: int(inarow), Levels: int(levels)}.WithDefaults()
 }
@@ -0,0 +1,179 @@
+package board
+
+import "math"
+
+// ========== Rendering ==========
+// Positions can be drawn as images: every cell is a grid of its
+// children, with the gaps between cells growing with how far out they
+// are, and X and O drawn in the spaces. Won cells get a large mark over
+// them, cells nobody can win anymore are shaded, and the cell the next
+// move has to be made in is highlighted. The same drawing goes to an
+// SVG image (see svg.go) or a raster one (see image.go).
+
+// rendering related constants
+const (
+	// the side of a space in pixels when none is given
+	RENDER_SPACE_SIZE = 40
+
+	RENDER_BACKGROUND = "#ffffff"
+	RENDER_LINE       = "#333333"
+	RENDER_X          = "#1f5fbf"
+	RENDER_O          = "#d1342f"
+	// the cell the next move has to be made in, matching the terminal's red
+	RENDER_TARGET = "#fde2e1"
+	// the last move, matching the terminal's yellow
+	RENDER_LAST_MOVE = "#ffe066"
+	RENDER_DRAWN     = "#d0d0d0"
+	RENDER_HEAT      = "#ff7f00"
+)
+
+// RenderOptions changes what positions are drawn with
+type RenderOptions struct {
+	// the side of a space in pixels; 0 means RENDER_SPACE_SIZE
+	SpaceSize int
+	// the move to highlight as the last one played; nil for none
+	LastMove *Move
+	// a value for every space by its index (see Size.MoveIndex), shown
+	// as heat over it scaled from the smallest value (or 0) up to the
+	// largest; nil for none
+	Heat []float64
+}
+
+// canvas is what positions are drawn on. Colors are RENDER_ constants
+type canvas interface {
+	// fills the rectangle
+	fill(x, y, w, h float64, color string, opacity float64)
+	// draws line segments, each x1, y1, x2, y2, with round ends as one
+	// shape, so that where they cross isn't painted twice
+	lines(segments [][4]float64, width float64, color string, opacity float64)
+	// draws the outline of a circle
+	ring(cx, cy, r, width float64, color string, opacity float64)
+}
+
+// the sizes the drawing of a position is laid out with
+type layout struct {
+	space, margin int
+	// the gap between the children of a node at each level, and the
+	// width and height of a node at each level
+	gaps, widths, heights []int
+}
+
+func newLayout(size Size, opts RenderOptions) layout {
+	space := opts.SpaceSize
+	if space <= 0 {
+		space = RENDER_SPACE_SIZE
+	}
+	l := layout{space: space, margin: space / 2}
+	l.gaps = make([]int, size.Levels+1)
+	l.widths, l.heights = make([]int, size.Levels+1), make([]int, size.Levels+1)
+	l.widths[size.Levels], l.heights[size.Levels] = space, space
+	for level := size.Levels - 1; level >= 0; level-- {
+		// spaces are split by lines, cells by gaps
+		l.gaps[level] = space / 4 * (size.Levels - 1 - level)
+		l.widths[level] = size.Cols*l.widths[level+1] + (size.Cols-1)*l.gaps[level]
+		l.heights[level] = size.Rows*l.heights[level+1] + (size.Rows-1)*l.gaps[level]
+	}
+	return l
+}
+
+// the width and height of the whole drawing
+func (l layout) bounds() (width, height int) {
+	return l.widths[0] + 2*l.margin, l.heights[0] + 2*l.margin
+}
+
+// draws the position on the canvas laid out by l
+func (p *Position) draw(c canvas, l layout, opts RenderOptions) {
+	width, height := l.bounds()
+	c.fill(0, 0, float64(width), float64(height), RENDER_BACKGROUND, 1)
+
+	last := int64(-1)
+	if opts.LastMove != nil {
+		if idx, valid := p.size.MoveIndex(opts.LastMove); valid {
+			last = int64(idx)
+		}
+	}
+	heat := heatLevels(opts.Heat, int(p.counts[p.size.Levels]))
+	p.drawNode(c, l, 0, 0, l.margin, l.margin, last, heat)
+}
+
+// scales the heat values to between 0 and 1, or returns nil if there
+// aren't any for every space
+func heatLevels(values []float64, spaces int) []float64 {
+	if len(values) != spaces {
+		return nil
+	}
+	lo, hi := 0.0, math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if hi <= lo {
+		return nil
+	}
+	levels := make([]float64, spaces)
+	for i, v := range values {
+		levels[i] = (v - lo) / (hi - lo)
+	}
+	return levels
+}
+
+// draws the node with the given index at the given level with its top
+// left corner at x, y, and everything in it
+func (p *Position) drawNode(c canvas, l layout, level int, idx uint32, x, y int, last int64, heat []float64) {
+	w, h := l.widths[level], l.heights[level]
+	fx, fy, fw, fh := float64(x), float64(y), float64(w), float64(h)
+	if level == p.size.Levels {
+		switch {
+		case int64(idx) == last:
+			c.fill(fx, fy, fw, fh, RENDER_LAST_MOVE, 1)
+		case heat != nil && heat[idx] > 0:
+			c.fill(fx, fy, fw, fh, RENDER_HEAT, 0.8*heat[idx])
+		}
+		drawMark(c, p.childOwner(level, idx), fx, fy, fw, fh, 1)
+		return
+	}
+
+	if level > 0 {
+		switch {
+		case level == p.targetLevel && idx == p.target:
+			c.fill(fx, fy, fw, fh, RENDER_TARGET, 1)
+		case p.closed(level, idx) && p.childOwner(level, idx) == Owner_NONE:
+			c.fill(fx, fy, fw, fh, RENDER_DRAWN, 1)
+		}
+	}
+
+	// children, then the lines between them
+	gap := l.gaps[level]
+	cw, ch := l.widths[level+1], l.heights[level+1]
+	for i := 0; i < p.size.Cells(); i++ {
+		row, col := i/p.size.Cols, i%p.size.Cols
+		child := idx*uint32(p.size.Cells()) + uint32(i)
+		p.drawNode(c, l, level+1, child, x+col*(cw+gap), y+row*(ch+gap), last, heat)
+	}
+	stroke := float64(p.size.Levels-level) * float64(l.space) / 40
+	for col := 1; col < p.size.Cols; col++ {
+		lx := float64(x+col*(cw+gap)) - float64(gap)/2
+		c.lines([][4]float64{{lx, fy, lx, fy + fh}}, stroke, RENDER_LINE, 1)
+	}
+	for row := 1; row < p.size.Rows; row++ {
+		ly := float64(y+row*(ch+gap)) - float64(gap)/2
+		c.lines([][4]float64{{fx, ly, fx + fw, ly}}, stroke, RENDER_LINE, 1)
+	}
+
+	if level > 0 {
+		drawMark(c, p.childOwner(level, idx), fx, fy, fw, fh, 0.4)
+	}
+}
+
+// draws an X or O filling the box, or nothing for NONE
+func drawMark(c canvas, owner Owner, x, y, w, h, opacity float64) {
+	side := math.Min(w, h)
+	pad, stroke := side/5, side/10
+	cx, cy := x+w/2, y+h/2
+	r := side/2 - pad
+	switch owner {
+	case Owner_PLAYER1:
+		c.lines([][4]float64{{cx - r, cy - r, cx + r, cy + r}, {cx + r, cy - r, cx - r, cy + r}}, stroke, RENDER_X, opacity)
+	case Owner_PLAYER2:
+		c.ring(cx, cy, r, stroke, RENDER_O, opacity)
+	}
+}
@@ -0,0 +1,134 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ========== Rules ==========
+
+// Rules are the parts of the game that differ between variants:
+// which cells can still be played in and how the game is decided
+type Rules interface {
+	// the name used to pick the rules and to advertise them to clients
+	Name() string
+
+	// whether or not moves can still be made in a cell
+	// with the given owner that is or isn't full
+	CellOpen(owner Owner, full bool) bool
+
+	// the result of the game in the position and who won, if anyone
+	Result(p *Position) (Result, Owner)
+}
+
+// every available ruleset
+var AllRules = []Rules{StandardRules{}, OpenCellRules{}, MisereRules{}, TiebreakRules{}}
+
+// returns the ruleset with the given name
+func RulesByName(name string) (Rules, error) {
+	for _, r := range AllRules {
+		if r.Name() == name {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown rules %q, expected one of %v", name, RuleNames())
+}
+
+// the names of every available ruleset, joined by commas
+func RuleNames() string {
+	names := make([]string, len(AllRules))
+	for i, r := range AllRules {
+		names[i] = r.Name()
+	}
+	return strings.Join(names, ", ")
+}
+
+// ========== Implementations ==========
+
+// StandardRules: cells close once they're won or full, and three
+// cells in a row wins
+type StandardRules struct{}
+
+func (StandardRules) Name() string {
+	return "standard"
+}
+func (StandardRules) CellOpen(owner Owner, full bool) bool {
+	return owner == Owner_NONE && !full
+}
+func (StandardRules) Result(p *Position) (Result, Owner) {
+	return decide(p, false)
+}
+
+// OpenCellRules: won cells can still be played in until they're full.
+// A cell belongs to whoever won it first
+type OpenCellRules struct{}
+
+func (OpenCellRules) Name() string {
+	return "open"
+}
+func (OpenCellRules) CellOpen(_ Owner, full bool) bool {
+	return !full
+}
+func (OpenCellRules) Result(p *Position) (Result, Owner) {
+	return decide(p, false)
+}
+
+// MisereRules: the standard rules, except that whoever gets
+// three cells in a row loses
+type MisereRules struct{}
+
+func (MisereRules) Name() string {
+	return "misere"
+}
+func (MisereRules) CellOpen(owner Owner, full bool) bool {
+	return StandardRules{}.CellOpen(owner, full)
+}
+func (MisereRules) Result(p *Position) (Result, Owner) {
+	return decide(p, true)
+}
+
+// TiebreakRules: the standard rules, except that a drawn game goes
+// to whoever won more cells
+type TiebreakRules struct{}
+
+func (TiebreakRules) Name() string {
+	return "tiebreak"
+}
+func (TiebreakRules) CellOpen(owner Owner, full bool) bool {
+	return StandardRules{}.CellOpen(owner, full)
+}
+func (TiebreakRules) Result(p *Position) (Result, Owner) {
+	result, winner := decide(p, false)
+	if result != Result_DRAW {
+		return result, winner
+	}
+
+	switch p1, p2 := p.cellCount(0), p.cellCount(1); {
+	case p1 > p2:
+		return Result_PLAYER1_WIN, Owner_PLAYER1
+	case p2 > p1:
+		return Result_PLAYER2_WIN, Owner_PLAYER2
+	}
+	return Result_DRAW, Owner_NONE
+}
+
+// decide returns the result of a position. A line of cells wins,
+// or loses if misere is set. Otherwise the game is a draw once no
+// cell is open anymore
+func decide(p *Position, misere bool) (Result, Owner) {
+	winner := p.owner()
+	if misere && winner != Owner_NONE {
+		winner = winner.Opponent()
+	}
+	switch winner {
+	case Owner_PLAYER1:
+		return Result_PLAYER1_WIN, winner
+	case Owner_PLAYER2:
+		return Result_PLAYER2_WIN, winner
+	}
+
+	if p.allClosed() {
+		return Result_DRAW, Owner_NONE
+	}
+	return Result_ONGOING, Owner_NONE
+}
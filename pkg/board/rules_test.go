@@ -0,0 +1,67 @@
+package board
+
+import "testing"
+
+// parses a position, failing the test if it can't be read
+func mustParse(t *testing.T, s string) *Position {
+	t.Helper()
+	p, err := ParsePosition(s)
+	if err != nil {
+		t.Fatalf("%s: %v", s, err)
+	}
+	return p
+}
+
+func TestResult(t *testing.T) {
+	// X has the top row of cells
+	line := "XXXXXXXXX/9/9/OO1OO1OO1/O2O2O2/9/9/9/9 - o"
+	// every cell is won, X won 5 and O 4 without a line
+	won := "XXXOOOXXX/9/9/XXXOOOOOO/9/9/OOOXXXXXX/9/9 - o"
+	// every cell is closed, both won 4 and the center is full
+	even := "XXXOOOXXX/9/9/XXXXOXOOO/3XOO3/3OXX3/OOOXXXOOO/9/9 - o"
+
+	tests := []struct {
+		position string
+		result   Result
+		winner   Owner
+	}{
+		{line, Result_PLAYER1_WIN, Owner_PLAYER1},
+		{line + " misere", Result_PLAYER2_WIN, Owner_PLAYER2},
+		{line + " tiebreak", Result_PLAYER1_WIN, Owner_PLAYER1},
+		{won, Result_DRAW, Owner_NONE},
+		{won + " misere", Result_DRAW, Owner_NONE},
+		{won + " tiebreak", Result_PLAYER1_WIN, Owner_PLAYER1},
+		{even + " tiebreak", Result_DRAW, Owner_NONE},
+		{"1X1O5/9/9/9/9/9/9/9/9 0 x misere", Result_ONGOING, Owner_NONE},
+	}
+	for _, test := range tests {
+		p := mustParse(t, test.position)
+		if result, winner := p.Result(); result != test.result || winner != test.winner {
+			t.Errorf("%s: result %v %v, expected %v %v", test.position, result, winner, test.result, test.winner)
+		}
+	}
+}
+
+// under the open rules won cells can still be played in, and keep
+// their first winner when the other player gets a line in them too
+func TestOpenCells(t *testing.T) {
+	cell := &Coord{Row: 0, Col: 0}
+	m := NewMove(cell, &Coord{Row: 1, Col: 2})
+
+	p := mustParse(t, "XXX6/OO7/9/9/9/9/9/9/9 0 o")
+	if p.Open(cell) || p.Legal(m) {
+		t.Fatalf("%s: cell 0 is open under the standard rules", p.Notation())
+	}
+
+	p = mustParse(t, "XXX6/OO7/9/9/9/9/9/9/9 0 o open")
+	if !p.Open(cell) || !p.Legal(m) {
+		t.Fatalf("%s: cell 0 is closed under the open rules", p.Notation())
+	}
+	p.Apply(m)
+	if owner := p.CellOwner(cell); owner != Owner_PLAYER1 {
+		t.Errorf("%s: cell 0 is owned by %v, expected PLAYER1", p.Notation(), owner)
+	}
+	if problems := Validate(p); len(problems) > 0 {
+		t.Errorf("%s: %v", p.Notation(), problems)
+	}
+}
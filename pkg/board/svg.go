@@ -0,0 +1,41 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ========== SVG ==========
+
+// svgCanvas draws on an SVG image
+type svgCanvas struct {
+	sb strings.Builder
+}
+
+func (c *svgCanvas) fill(x, y, w, h float64, color string, opacity float64) {
+	fmt.Fprintf(&c.sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="%.2f"/>`+"\n", x, y, w, h, color, opacity)
+}
+func (c *svgCanvas) lines(segments [][4]float64, width float64, color string, opacity float64) {
+	var d strings.Builder
+	for _, s := range segments {
+		fmt.Fprintf(&d, "M%.1f %.1fL%.1f %.1f", s[0], s[1], s[2], s[3])
+	}
+	fmt.Fprintf(&c.sb, `<path d="%s" stroke="%s" stroke-width="%.1f" stroke-linecap="round" stroke-opacity="%.2f"/>`+"\n",
+		d.String(), color, width, opacity)
+}
+func (c *svgCanvas) ring(cx, cy, r, width float64, color string, opacity float64) {
+	fmt.Fprintf(&c.sb, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="%s" stroke-width="%.1f" stroke-opacity="%.2f"/>`+"\n",
+		cx, cy, r, color, width, opacity)
+}
+
+// SVG returns the position drawn as an SVG image
+func (p *Position) SVG(opts RenderOptions) string {
+	l := newLayout(p.size, opts)
+	width, height := l.bounds()
+
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	p.draw(c, l, opts)
+	c.sb.WriteString("</svg>\n")
+	return c.sb.String()
+}
@@ -0,0 +1,258 @@
+package board
+
+import "math/bits"
+
+// ========== Symmetry ==========
+
+// Symmetry is one of the 8 rotations and reflections of a square.
+// Applying one to a board moves every cell, and every space in every
+// cell, the same way, so the position stays just as good for both
+// players. Boards with a different number of rows and columns only
+// have the symmetries that don't swap rows with columns
+type Symmetry int
+
+const (
+	Identity Symmetry = iota
+	// clockwise rotations
+	Rotate90
+	Rotate180
+	Rotate270
+	// mirrors left to right
+	FlipHorizontal
+	// mirrors top to bottom
+	FlipVertical
+	// mirrors along the diagonal from the top left, swapping rows with columns
+	FlipDiagonal
+	// mirrors along the diagonal from the top right
+	FlipAntiDiagonal
+)
+
+// every symmetry, starting with Identity
+var AllSymmetries = []Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipHorizontal, FlipVertical, FlipDiagonal, FlipAntiDiagonal}
+
+var symmetryNames = map[Symmetry]string{
+	Identity:         "identity",
+	Rotate90:         "rotate90",
+	Rotate180:        "rotate180",
+	Rotate270:        "rotate270",
+	FlipHorizontal:   "fliph",
+	FlipVertical:     "flipv",
+	FlipDiagonal:     "flipdiag",
+	FlipAntiDiagonal: "flipanti",
+}
+
+func (s Symmetry) String() string {
+	return symmetryNames[s]
+}
+
+// the symmetry that undoes s
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	}
+	return s
+}
+
+// whether or not s swaps rows with columns
+func (s Symmetry) transposes() bool {
+	switch s {
+	case Rotate90, Rotate270, FlipDiagonal, FlipAntiDiagonal:
+		return true
+	}
+	return false
+}
+
+// the symmetries that map a board of this size onto itself;
+// all 8 for square boards, otherwise the 4 that don't swap rows with columns
+func (s Size) Symmetries() []Symmetry {
+	if s.Rows == s.Cols {
+		return AllSymmetries
+	}
+	var syms []Symmetry
+	for _, sym := range AllSymmetries {
+		if !sym.transposes() {
+			syms = append(syms, sym)
+		}
+	}
+	return syms
+}
+
+// applies s to a coordinate of a board of the given size.
+// Coordinates that aren't on the board are copied as they are
+func (s Symmetry) Coord(size Size, c *Coord) *Coord {
+	if !size.Contains(c) {
+		if c == nil {
+			return nil
+		}
+		return &Coord{Row: c.Row, Col: c.Col}
+	}
+	lastRow, lastCol := int32(size.Rows-1), int32(size.Cols-1)
+	row, col := c.Row, c.Col
+	switch s {
+	case Rotate90:
+		row, col = c.Col, lastRow-c.Row
+	case Rotate180:
+		row, col = lastRow-c.Row, lastCol-c.Col
+	case Rotate270:
+		row, col = lastCol-c.Col, c.Row
+	case FlipHorizontal:
+		col = lastCol - c.Col
+	case FlipVertical:
+		row = lastRow - c.Row
+	case FlipDiagonal:
+		row, col = c.Col, c.Row
+	case FlipAntiDiagonal:
+		row, col = lastCol-c.Col, lastRow-c.Row
+	}
+	return &Coord{Row: row, Col: col}
+}
+
+// applies s to every coordinate of a move on a board of the given size
+func (s Symmetry) Move(size Size, m *Move) *Move {
+	path := m.Path()
+	for i, c := range path {
+		path[i] = s.Coord(size, c)
+	}
+	return NewMove(path...)
+}
+
+// applies s to the index of a cell in row-major order
+func (s Symmetry) index(size Size, idx uint32) uint32 {
+	t, _ := size.Index(s.Coord(size, size.Coord(idx)))
+	return t
+}
+
+// applies s to the index of a cell (or space) among all the cells of
+// its level, where depth is the level
+func (s Symmetry) pathIndex(size Size, idx uint32, depth int) uint32 {
+	n := uint32(size.Cells())
+	var t, scale uint32 = 0, 1
+	for i := 0; i < depth; i++ {
+		t += s.index(size, idx%n) * scale
+		idx /= n
+		scale *= n
+	}
+	return t
+}
+
+// Board returns a copy of the board with s applied to it, including
+// the cell the next move has to be made in. s should be one of the
+// board size's Symmetries
+func (s Symmetry) Board(b *Board) *Board {
+	size := b.Size()
+	t := &Board{Cells: s.cells(size, b.Cells), Rows: b.Rows, Cols: b.Cols, Inarow: b.Inarow, Levels: b.Levels}
+	if b.CurCell != nil {
+		t.CurCell = s.Coord(size, b.CurCell)
+	}
+	for _, c := range b.CurCells {
+		t.CurCells = append(t.CurCells, s.Coord(size, c))
+	}
+	return t
+}
+
+// copies the cells with s applied to them and everything in them
+func (s Symmetry) cells(size Size, cells []*Cell) []*Cell {
+	if cells == nil {
+		return nil
+	}
+	moved := len(cells) == size.Cells()
+	out := make([]*Cell, len(cells))
+	for i, cell := range cells {
+		t := &Cell{Winner: cell.Winner, Cells: s.cells(size, cell.Cells)}
+		if cell.Spaces != nil {
+			t.Spaces = make([]*Space, len(cell.Spaces))
+			for j, space := range cell.Spaces {
+				if len(cell.Spaces) == size.Cells() {
+					j = int(s.index(size, uint32(j)))
+				}
+				t.Spaces[j] = &Space{Val: space.Val}
+			}
+		}
+		if moved {
+			i = int(s.index(size, uint32(i)))
+		}
+		out[i] = t
+	}
+	return out
+}
+
+// Position returns a copy of the position with s applied to it,
+// including the cell the next move has to be made in. s should be one
+// of the position size's Symmetries
+func (s Symmetry) Position(p *Position) *Position {
+	t := &Position{rules: p.rules, turn: p.turn, staleTarget: p.staleTarget}
+	t.init(p.size)
+	n := uint32(p.g.n)
+
+	// where each child of a node goes
+	var perm [MAX_CELLS]uint32
+	for i := uint32(0); i < n; i++ {
+		perm[i] = s.index(p.size, i)
+	}
+	permute := func(mask uint64) uint64 {
+		var out uint64
+		for ; mask != 0; mask &= mask - 1 {
+			out |= 1 << perm[bits.TrailingZeros64(mask)]
+		}
+		return out
+	}
+
+	for level := 0; level < p.size.Levels; level++ {
+		for idx := uint32(0); idx < p.counts[level]; idx++ {
+			nd := p.node(level, idx)
+			*t.node(level, s.pathIndex(p.size, idx, level)) = node{
+				owned:  [2]uint64{permute(nd.owned[0]), permute(nd.owned[1])},
+				closed: permute(nd.closed),
+			}
+		}
+	}
+	t.targetLevel, t.target = p.targetLevel, s.pathIndex(p.size, p.target, p.targetLevel)
+	t.key = t.computeKey()
+	return t
+}
+
+// Key returns the zobrist key the position would have with s applied
+// to it, without building the transformed position
+func (s Symmetry) Key(p *Position) uint64 {
+	size := p.size
+	var key uint64
+	p.eachSpace(func(space uint32, owner Owner) {
+		key ^= zobristSpace(owner, s.pathIndex(size, space, size.Levels))
+	})
+	key ^= zobristCur(p.targetLevel, s.pathIndex(size, p.target, p.targetLevel))
+	if p.turn == Owner_PLAYER2 {
+		key ^= zobristTurn
+	}
+	return key
+}
+
+// the symmetry mapping the position to its canonical form and the key
+// of it. Ties go to the first symmetry in AllSymmetries
+func canonical(p *Position) (Symmetry, uint64) {
+	best, bestKey := Identity, p.Key()
+	for _, s := range p.size.Symmetries()[1:] {
+		if key := s.Key(p); key < bestKey {
+			best, bestKey = s, key
+		}
+	}
+	return best, bestKey
+}
+
+// Canonical returns the canonical form of the position: whichever of
+// the position under each of its size's symmetries has the smallest
+// key. Positions that are symmetric to each other share a canonical
+// form. It also returns the symmetry that maps the position to it;
+// apply its Inverse to map moves on the canonical form back
+func Canonical(p *Position) (*Position, Symmetry) {
+	s, _ := canonical(p)
+	return s.Position(p), s
+}
+
+// the key of the position's canonical form, without building it
+func CanonicalKey(p *Position) uint64 {
+	_, key := canonical(p)
+	return key
+}
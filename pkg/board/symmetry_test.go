@@ -0,0 +1,59 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSymmetryKey(t *testing.T) {
+	playRandomGames(t, 2, func(t *testing.T, p *Position) {
+		// every position takes too long on the larger boards
+		if (p.Count(Owner_PLAYER1)+p.Count(Owner_PLAYER2))%7 != 0 {
+			return
+		}
+		for _, s := range p.Size().Symmetries() {
+			q := s.Position(p)
+			if key := q.computeKey(); s.Key(p) != key {
+				t.Fatalf("%s under %s: key %x, recomputed %x", p.Notation(), s, s.Key(p), key)
+			}
+			// the inverse takes the position back, key and all
+			if back := s.Inverse().Position(q); back.Key() != p.Key() {
+				t.Fatalf("%s under %s and back: key %x, expected %x", p.Notation(), s, back.Key(), p.Key())
+			}
+		}
+
+		c, s := Canonical(p)
+		if c.Key() != c.computeKey() || c.Key() != CanonicalKey(p) || c.Key() != s.Key(p) {
+			t.Fatalf("%s: canonical key %x, recomputed %x, CanonicalKey %x", p.Notation(), c.Key(), c.computeKey(), CanonicalKey(p))
+		}
+		for _, s := range p.Size().Symmetries() {
+			if key := CanonicalKey(s.Position(p)); key != c.Key() {
+				t.Fatalf("%s under %s: canonical key %x, expected %x", p.Notation(), s, key, c.Key())
+			}
+		}
+	})
+}
+
+// keys kept up as moves are played agree with the symmetry's key when
+// the same game is played through the symmetry
+func TestSymmetryKeyIncremental(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, size := range testSizes {
+		for _, s := range size.Symmetries() {
+			p := NewPosition(size, StandardRules{})
+			q := s.Position(p)
+			for {
+				if result, _ := p.Result(); result != Result_ONGOING {
+					break
+				}
+				moves := p.Moves()
+				m := moves[rng.Intn(len(moves))]
+				p.Apply(m)
+				q.Apply(s.Move(size, m))
+				if q.Key() != s.Key(p) {
+					t.Fatalf("%s under %s: key %x, expected %x", p.Notation(), s, q.Key(), s.Key(p))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package board
+
+import "math/bits"
+
+// ========== Threats ==========
+// A threat is a line in a cell, or on the board of outermost cells,
+// that a player can complete with one more won cell or claimed space.
+// Only lines that can still be completed count: the cell (and every
+// cell holding it) has to be open, and the missing cell or space can't
+// belong to anyone or be closed.
+
+// Threat is a line that Player can complete by winning one more cell
+// (or claiming one more space) inside Cell, at the coordinate At.
+// Cell holds the coordinates of the cell, outermost first; it's empty
+// for lines of outermost cells
+type Threat struct {
+	Player Owner
+	Cell   []*Coord
+	At     *Coord
+}
+
+// Threats returns every threat the player has, starting with the board
+// of outermost cells and then level by level in row-major order.
+// Lines completed at the same coordinate of the same cell are one threat.
+// There are none once the game is over
+func (p *Position) Threats(player Owner) []Threat {
+	if result, _ := p.Result(); result != Result_ONGOING {
+		return nil
+	}
+	pi, valid := playerIndex(player)
+	if !valid {
+		return nil
+	}
+
+	var threats []Threat
+	for level := 0; level < p.size.Levels; level++ {
+		for idx := uint32(0); idx < p.counts[level]; idx++ {
+			if p.open(level, idx) {
+				threats = p.appendThreats(threats, level, idx, player, p.threats(level, idx, pi))
+			}
+		}
+	}
+	return threats
+}
+
+// the children of the node that complete a line for the player with
+// the given index
+func (p *Position) threats(level int, idx uint32, player int) uint64 {
+	nd := *p.node(level, idx)
+	return p.g.threats(nd.owned[player], p.available(nd))
+}
+
+// appends a Threat for every child of the node in the mask
+func (p *Position) appendThreats(dst []Threat, level int, idx uint32, player Owner, mask uint64) []Threat {
+	var cell []*Coord
+	if level > 0 {
+		cell = p.size.Path(idx, level)
+	}
+	for ; mask != 0; mask &= mask - 1 {
+		at := p.size.Coord(uint32(bits.TrailingZeros64(mask)))
+		dst = append(dst, Threat{Player: player, Cell: cell, At: at})
+	}
+	return dst
+}
+
+// the index of the player's masks in a node
+func playerIndex(player Owner) (int, bool) {
+	switch player {
+	case Owner_PLAYER1:
+		return 0, true
+	case Owner_PLAYER2:
+		return 1, true
+	}
+	return 0, false
+}
@@ -0,0 +1,45 @@
+package board
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestThreats(t *testing.T) {
+	// the rest of the rows of a 3 level board, after the first
+	rows3 := strings.Repeat("/27", 26)
+	tests := []struct {
+		name, position string
+		x, o           []string
+	}{
+		{"empty", "9/9/9/9/9/9/9/9/9 - x", nil, nil},
+		{"two in a row", "XX7/9/9/9/9/9/9/9/9 - o", []string{"0@2"}, nil},
+		{"blocked", "XXO6/X8/9/9/9/9/9/9/9 - o", []string{"0@6"}, nil},
+		{"both players", "XX1OO4/9/9/9/9/9/9/9/9 - x", []string{"0@2"}, []string{"1@2"}},
+		{"closed cell", "XXX6/OO7/9/9/9/9/9/9/9 - o", nil, nil},
+		{"open cell", "XXX6/OO7/9/9/9/9/9/9/9 - o open", nil, []string{"0@5"}},
+		{"cells", "XXXXXX3/9/9/9/9/9/9/9/9 - o", []string{"-@2"}, nil},
+		{"blocked cells", "XXXXXXOOO/9/9/9/9/9/9/9/9 - x", nil, nil},
+		{"cells and spaces", "XXXXXX3/9/9/9/9/9/9/9/OO7 - x", []string{"-@2"}, []string{"6@8"}},
+		{"inner cells", "XX25" + rows3 + " - o 3x3l3", []string{"0.0@2"}, nil},
+		{"outer cells", "XXXXXX21" + rows3 + " - o 3x3l3", []string{"0@2"}, nil},
+		{"game over", "XXXXXXXXX/9/9/OO7/9/9/9/9/9 - o", nil, nil},
+	}
+	for _, test := range tests {
+		p := mustParse(t, test.position)
+		if x := threatStrings(p.Size(), p.Threats(Owner_PLAYER1)); !reflect.DeepEqual(x, test.x) {
+			t.Errorf("%s: PLAYER1 threatens %v, expected %v", test.name, x, test.x)
+		}
+		if o := threatStrings(p.Size(), p.Threats(Owner_PLAYER2)); !reflect.DeepEqual(o, test.o) {
+			t.Errorf("%s: PLAYER2 threatens %v, expected %v", test.name, o, test.o)
+		}
+		for _, owner := range []Owner{Owner_PLAYER1, Owner_PLAYER2} {
+			for _, threat := range p.Threats(owner) {
+				if threat.Player != owner {
+					t.Errorf("%s: threat of %v listed for %v", test.name, threat.Player, owner)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ========== Validation ==========
+// Positions built by playing moves are always consistent, but positions
+// loaded from files or received from clients can be anything that has
+// the right shape. Validate finds what couldn't happen in a real game.
+
+// Validate returns every problem that keeps the position from coming
+// up in a game played under its rules; none if it could come up.
+// It doesn't check that the moves could have been made in some order,
+// only what follows from the last one
+func Validate(p *Position) []error {
+	var problems []error
+	c1, c2 := p.count(0), p.count(1)
+	counted := c1 == c2 || c1 == c2+1
+	if !counted {
+		problems = append(problems, fmt.Errorf("PLAYER1 claimed %d spaces and PLAYER2 %d, but they take turns starting with PLAYER1", c1, c2))
+	} else if turn := turnOf(c1, c2); p.turn != turn {
+		problems = append(problems, fmt.Errorf("it's %v's turn after %d moves", p.turn, c1+c2))
+	}
+
+	// won cells close for good unless the rules say otherwise,
+	// so only one player can have a line in them
+	closes := !p.rules.CellOpen(Owner_PLAYER1, false)
+	for level := 1; level < p.size.Levels; level++ {
+		for idx := uint32(0); idx < p.counts[level]; idx++ {
+			nd, owner := p.node(level, idx), p.childOwner(level, idx)
+			won1, won2 := p.g.won(nd.owned[0]), p.g.won(nd.owned[1])
+			switch {
+			case owner == Owner_PLAYER1 && !won1, owner == Owner_PLAYER2 && !won2:
+				problems = append(problems, fmt.Errorf("%s is won by %v without a line", p.cellName(level, idx), owner))
+			case closes && won1 && won2:
+				problems = append(problems, fmt.Errorf("both players have a line in %s, but it closed once it was won", p.cellName(level, idx)))
+			}
+		}
+	}
+
+	// the game ends with the first line of cells
+	root := p.nodes[0]
+	if p.g.won(root.owned[0]) && p.g.won(root.owned[1]) {
+		problems = append(problems, fmt.Errorf("both players have a line of cells"))
+	} else if owner := p.owner(); counted && owner != Owner_NONE && owner != p.turn.Opponent() {
+		problems = append(problems, fmt.Errorf("play continued after %v completed a line of cells", owner))
+	}
+
+	if p.staleTarget {
+		problems = append(problems, fmt.Errorf("curCell points at a closed cell"))
+	} else if counted && c1+c2 > 0 && !p.followsFrom(p.turn.Opponent()) {
+		problems = append(problems, fmt.Errorf("no move by %v sends the next move to %s", p.turn.Opponent(), p.cellName(p.targetLevel, p.target)))
+	}
+	return problems
+}
+
+// Check returns an error listing every problem Validate finds in the
+// position, or nil if it could come up in a game. Loaders use it to
+// reject positions from files and clients
+func Check(p *Position) error {
+	if problems := Validate(p); len(problems) > 0 {
+		return fmt.Errorf("impossible position:\n%w", errors.Join(problems...))
+	}
+	return nil
+}
+
+// ParseValidPosition reads a position like ParsePosition, returning
+// an error from Check if it couldn't come up in a game
+func ParseValidPosition(s string) (*Position, error) {
+	p, err := ParsePosition(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := Check(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// whose turn it is once the players have claimed the given numbers of spaces
+func turnOf(c1, c2 int) Owner {
+	if c1 == c2 {
+		return Owner_PLAYER1
+	}
+	return Owner_PLAYER2
+}
+
+// whether or not one of the player's moves sends the next move to
+// where the position has it
+func (p *Position) followsFrom(player Owner) bool {
+	found := false
+	p.eachSpace(func(space uint32, owner Owner) {
+		if owner != player || found {
+			return
+		}
+		level, idx := p.sentTo(space)
+		found = level == p.targetLevel && idx == p.target
+	})
+	return found
+}
+
+// names the cell the way Notation does
+func (p *Position) cellName(level int, idx uint32) string {
+	if level == 0 {
+		return "any cell"
+	}
+	return "cell " + p.size.cellString(level, idx)
+}
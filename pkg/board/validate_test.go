@@ -0,0 +1,78 @@
+package board
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name, position string
+		// part of one of the problems found; empty if there are none
+		problem string
+	}{
+		{"empty board", "9/9/9/9/9/9/9/9/9 - x", ""},
+		{"after two moves", "1X1O5/9/9/9/9/9/9/9/9 0 x", ""},
+		{"too many marks", "XXX6/9/9/9/9/9/9/9/9 0 o", "take turns"},
+		{"wrong side to move", "1X1O5/9/9/9/9/9/9/9/9 0 o", "turn after 2 moves"},
+		{"forced into a won cell", "XXX6/9/9/OO7/9/9/9/9/9 0 o", "closed cell"},
+		{"forced into a full cell", "XOX6/XOO6/OXX6/9/9/9/9/9/9 0 o", "closed cell"},
+		{"not sent there", "1X1O5/9/9/9/9/9/9/9/9 4 x", "sends the next move to cell 4"},
+		{"play after a line of cells", "XXXXXXXXX/9/9/OO1OO1OO1/O2O2O2/9/9/9/9 - x", "play continued"},
+		{"both have a line of cells", "XXXXXXXXX/9/9/OOOOOOOOO/9/9/9/9/9 - x", "both players"},
+	}
+	for _, test := range tests {
+		p, err := ParsePosition(test.position)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		problems := Validate(p)
+		if test.problem == "" {
+			for _, problem := range problems {
+				t.Errorf("%s: unexpected problem: %v", test.name, problem)
+			}
+			continue
+		}
+		found := false
+		for _, problem := range problems {
+			found = found || strings.Contains(problem.Error(), test.problem)
+		}
+		if !found {
+			t.Errorf("%s: expected a problem with %q, got %v", test.name, test.problem, problems)
+		}
+	}
+}
+
+// positions reached by playing moves always pass
+func TestValidatePlayed(t *testing.T) {
+	playRandomGames(t, 5, func(t *testing.T, p *Position) {
+		if problems := Validate(p); len(problems) > 0 {
+			t.Fatalf("%s: %v", p.Notation(), problems)
+		}
+	})
+}
+
+// positions and boards from clients are rejected if they're impossible
+func TestFromProto(t *testing.T) {
+	for s, valid := range map[string]bool{
+		"1X1O5/9/9/9/9/9/9/9/9 0 x": true,
+		"XXX6/9/9/9/9/9/9/9/9 0 o":  false,
+	} {
+		if _, err := ParseValidPosition(s); (err == nil) != valid {
+			t.Errorf("%s: ParseValidPosition returned %v", s, err)
+		}
+		if _, err := FromProto(mustParse(t, s).ToProto(), StandardRules{}); (err == nil) != valid {
+			t.Errorf("%s: FromProto returned %v", s, err)
+		}
+	}
+
+	playRandomGames(t, 2, func(t *testing.T, p *Position) {
+		q, err := FromProto(p.ToProto(), p.Rules())
+		if err != nil {
+			t.Fatalf("%s: %v", p.Notation(), err)
+		}
+		if q.Notation() != p.Notation() {
+			t.Fatalf("%s: converted back as %s", p.Notation(), q.Notation())
+		}
+	})
+}
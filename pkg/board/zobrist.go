@@ -0,0 +1,76 @@
+package board
+
+// ========== Zobrist Keys ==========
+// A position's key is the xor of a random number for every claimed
+// space, one for the cell the next move has to be made in and one if
+// it's PLAYER2's turn. Since xor undoes itself, making or taking back
+// a move only has to xor in the numbers that changed.
+
+// the seed of the key numbers. It's fixed so that keys are stable across
+// runs and can be stored in opening books and datasets
+const zobristSeed = 0x75747474
+
+// what a key number is for; the numbers of different kinds never collide
+const (
+	zobristKindPlayer1 = iota
+	zobristKindPlayer2
+	zobristKindTurn
+	// followed by one kind per level of the cell the next move is in
+	zobristKindCur
+)
+
+// xor'ed in when it's PLAYER2's turn
+var zobristTurn = zobristNumber(zobristKindTurn, 0)
+
+// returns the number of the splitmix64 sequence following state
+func splitmix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// the random number for the given kind and index. Boards can have too
+// many spaces to keep a table of numbers, so they're computed on the fly
+func zobristNumber(kind, idx uint32) uint64 {
+	state := (uint64(kind)<<32 | uint64(idx)) ^ zobristSeed
+	return splitmix64(&state)
+}
+
+// the number for the space with the given index claimed by owner,
+// or 0 if it isn't claimed
+func zobristSpace(owner Owner, space uint32) uint64 {
+	switch owner {
+	case Owner_PLAYER1:
+		return zobristNumber(zobristKindPlayer1, space)
+	case Owner_PLAYER2:
+		return zobristNumber(zobristKindPlayer2, space)
+	}
+	return 0
+}
+
+// the number for the cell the next move has to be made in,
+// given by its level and index as returned by Size.target
+func zobristCur(level int, cell uint32) uint64 {
+	return zobristNumber(zobristKindCur+uint32(level), cell)
+}
+
+// computes the position's zobrist key from scratch
+func (p *Position) computeKey() uint64 {
+	var key uint64
+	p.eachSpace(func(space uint32, owner Owner) {
+		key ^= zobristSpace(owner, space)
+	})
+	key ^= zobristCur(p.targetLevel, p.target)
+	if p.turn == Owner_PLAYER2 {
+		key ^= zobristTurn
+	}
+	return key
+}
+
+// Key returns the position's zobrist key. It's kept up to date as
+// moves are applied and undone
+func (p *Position) Key() uint64 {
+	return p.key
+}
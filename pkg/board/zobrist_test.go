@@ -0,0 +1,56 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// the sizes the tests play on, including one that isn't square and
+// so has fewer symmetries
+var testSizes = []Size{
+	DefaultSize(),
+	{Rows: 4, Cols: 4, InARow: 3, Levels: 2},
+	{Rows: 2, Cols: 3, InARow: 2, Levels: 2},
+	{Rows: 3, Cols: 3, InARow: 3, Levels: 3},
+}
+
+// plays random games under every ruleset on every test size, calling
+// check with each position reached, and undoing every game back to
+// the start once it's over
+func playRandomGames(t *testing.T, games int, check func(t *testing.T, p *Position)) {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range testSizes {
+		for _, r := range AllRules {
+			for i := 0; i < games; i++ {
+				p := NewPosition(size, r)
+				check(t, p)
+				var undos []UndoRecord
+				var keys []uint64
+				for {
+					if result, _ := p.Result(); result != Result_ONGOING {
+						break
+					}
+					moves := p.Moves()
+					keys = append(keys, p.Key())
+					undos = append(undos, p.Apply(moves[rng.Intn(len(moves))]))
+					check(t, p)
+				}
+				for j := len(undos) - 1; j >= 0; j-- {
+					p.Undo(undos[j])
+					if p.Key() != keys[j] {
+						t.Fatalf("%s %s: key after undoing move %d is %x, was %x", size.Tag(), r.Name(), j+1, p.Key(), keys[j])
+					}
+					check(t, p)
+				}
+			}
+		}
+	}
+}
+
+func TestKeyMatchesRecompute(t *testing.T) {
+	playRandomGames(t, 20, func(t *testing.T, p *Position) {
+		if key := p.computeKey(); p.Key() != key {
+			t.Fatalf("%s: incremental key %x, recomputed %x", p.Notation(), p.Key(), key)
+		}
+	})
+}
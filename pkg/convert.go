@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"uttt/pkg/board"
+	"uttt/pkg/db"
+	"uttt/pkg/record"
+)
+
+// runs `uttt convert [flags] <moves... | file>`, printing games with
+// their moves in another convention (see board.Convention). The moves
+// are a game given as arguments, or a file of game records or of a game
+// per line (skipping blank lines and ones starting with #). Every game
+// is replayed to check its moves, and can be added to a game database
+func convert(args []string) {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	fromName := flags.String("from", board.NativeConvention.String(), "the convention the moves are in, unless they're game records; one of "+board.ConventionNames())
+	toName := flags.String("to", board.NativeConvention.String(), "the convention to print the moves in; one of "+board.ConventionNames())
+	sizeTag := flags.String("size", board.DefaultSize().Tag(), "the size of the board the moves are on, unless they're game records, e.g. 4x4k3")
+	rulesName := flags.String("rules", board.StandardRules{}.Name(), "the rules the games were played by, unless they're game records; one of "+board.RuleNames())
+	dbPath := flags.String("record", "", "a game database to add the games to; empty to only print them")
+	player1 := flags.String("player1", "?", "the name of the player who moved first, for games added to the database")
+	player2 := flags.String("player2", "?", "the name of the player who moved second, for games added to the database")
+	flags.Parse(args)
+	if flags.NArg() < 1 {
+		fmt.Println("usage: uttt convert [flags] <moves... | file>")
+		os.Exit(2)
+	}
+
+	from, err := board.ParseConvention(*fromName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	to, err := board.ParseConvention(*toName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	template := &record.Record{Player1: *player1, Player2: *player2, Date: time.Now(), Opening: board.FreeOpening, Comments: map[int]string{}}
+	if template.Size, err = board.ParseSize(*sizeTag); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if template.Rules, err = board.RulesByName(*rulesName); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	records, native, err := convertedRecords(flags.Args(), from, template)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if native {
+		from = board.NativeConvention
+	}
+	var d *db.DB
+	if *dbPath != "" {
+		d = db.Open(*dbPath)
+	}
+	for i, rec := range records {
+		// replaying checks the moves and decides how the game ended
+		p, err := rec.Start()
+		if err != nil {
+			fmt.Printf("game %d: %v\n", i+1, err)
+			os.Exit(1)
+		}
+		for j, m := range rec.Moves {
+			if !p.Legal(m) {
+				fmt.Printf("game %d: move %d (%s) isn't legal\n", i+1, j+1, rec.Size.FormatMove(m, from))
+				os.Exit(1)
+			}
+			p.Apply(m)
+		}
+		if rec.Result == board.Result_ONGOING {
+			rec.Result, rec.Winner = p.Result()
+		}
+		fmt.Println(rec.Size.FormatMoves(rec.Moves, to))
+
+		if d != nil {
+			if err := d.Append(rec); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	}
+}
+
+// the games in the args as records, and whether or not they were game
+// records already. Games that aren't are copies of template with their
+// moves read in the convention
+func convertedRecords(args []string, from board.Convention, template *record.Record) (records []*record.Record, native bool, err error) {
+	game := func(text string) (*record.Record, error) {
+		moves, err := template.Size.ParseMoves(text, from)
+		if err != nil {
+			return nil, err
+		}
+		rec := *template
+		rec.Moves, rec.Comments = moves, map[int]string{}
+		return &rec, nil
+	}
+
+	if _, err := os.Stat(args[0]); err != nil || len(args) > 1 {
+		rec, err := game(strings.Join(args, " "))
+		if err != nil {
+			return nil, false, err
+		}
+		return []*record.Record{rec}, false, nil
+	}
+	if records, err := record.ReadFile(args[0]); err == nil && len(records) > 0 {
+		return records, true, nil
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rec, err := game(line)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s line %d: %w", args[0], lineNum, err)
+		}
+		records = append(records, rec)
+	}
+	return records, false, scanner.Err()
+}
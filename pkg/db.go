@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"uttt/pkg/board"
+	"uttt/pkg/db"
+)
+
+// runs `uttt db [flags]`, listing the games in the database that
+// match the flags, printing their records with --records or writing
+// them as GameRecord messages with --stream
+func queryDB(args []string) {
+	flags := flag.NewFlagSet("db", flag.ExitOnError)
+	path := flags.String("record", "games.uttt", "the game database to query")
+	winners := flags.String("winner", "", "who won, split by commas; x, o or none")
+	results := flags.String("result", "", "how the game ended, split by commas; win, draw, resignation, timeout, forfeit, abandoned or ongoing")
+	var q db.Query
+	flags.IntVar(&q.MinMoves, "min-moves", 0, "the fewest moves made")
+	flags.IntVar(&q.MaxMoves, "max-moves", 0, "the most moves made; 0 means no limit")
+	flags.StringVar(&q.Player, "player", "", "a name either player had")
+	flags.StringVar(&q.Player1, "player1", "", "the name of the player who moved first")
+	flags.StringVar(&q.Player2, "player2", "", "the name of the player who moved second")
+	flags.StringVar(&q.Rules, "rules", "", "the rules played by; one of "+board.RuleNames())
+	size := flags.String("size", "", "the size of the board, e.g. 3x3 or 4x4k3; see show")
+	opening := flags.String("opening", "", "where the first move could be made; free, center, or the index of a cell")
+	start := flags.String("start", "", "the moves the game started with, split by commas, e.g. 4.4,4.0")
+	limit := flags.Int("limit", 0, "the most games to list; 0 means no limit")
+	records := flags.Bool("records", false, "print the games' records instead of listing them")
+	stream := flags.String("stream", "", "a file to write the games to as a stream of GameRecord messages instead of listing them")
+	flags.Parse(args)
+
+	for _, w := range splitList(*winners) {
+		switch w {
+		case "x":
+			q.Winners = append(q.Winners, board.Owner_PLAYER1)
+		case "o":
+			q.Winners = append(q.Winners, board.Owner_PLAYER2)
+		case "none":
+			q.Winners = append(q.Winners, board.Owner_NONE)
+		default:
+			fmt.Printf("invalid winner %q, expected x, o or none\n", w)
+			os.Exit(2)
+		}
+	}
+	for _, r := range splitList(*results) {
+		switch r {
+		case "win":
+			q.Results = append(q.Results, board.Result_PLAYER1_WIN, board.Result_PLAYER2_WIN)
+		case "draw", "resignation", "timeout", "forfeit", "abandoned", "ongoing":
+			q.Results = append(q.Results, board.Result(board.Result_value[strings.ToUpper(r)]))
+		default:
+			fmt.Printf("invalid result %q, expected win, draw, resignation, timeout, forfeit, abandoned or ongoing\n", r)
+			os.Exit(2)
+		}
+	}
+	// sizes and openings are matched in the format they're indexed in
+	boardSize := board.DefaultSize()
+	if *size != "" {
+		var err error
+		if boardSize, err = board.ParseSize(*size); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		q.Board = boardSize.Tag()
+	}
+	if *opening != "" {
+		o, err := board.ParseOpening(*opening, boardSize)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		q.Opening = o.String()
+	}
+	q.Start = splitList(*start)
+
+	d := db.Open(*path)
+	entries, err := d.Find(q, *limit)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *stream != "" {
+		f, err := os.Create(*stream)
+		if err == nil {
+			err = d.WriteStream(f, entries)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %d games to %s\n", len(entries), *stream)
+		return
+	}
+	if *records {
+		for _, e := range entries {
+			rec, err := d.Record(e)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			rec.Write(os.Stdout)
+		}
+		return
+	}
+
+	// numbers line up with `uttt replay`
+	wins := map[board.Owner]int{}
+	moves := 0
+	for _, e := range entries {
+		fmt.Printf("%d\t%s\t%s vs %s\t%s, %s\t%v\t%d moves\t%s\n", e.Game, e.Date, e.Player1, e.Player2, e.Rules, e.Board, e.Result, e.Moves, strings.Join(e.Start, " "))
+		wins[e.Winner]++
+		moves += e.Moves
+	}
+	if len(entries) > 0 {
+		fmt.Printf("\n%d games: %d PLAYER1 wins, %d PLAYER2 wins, %d without a winner, %.1f moves on average\n",
+			len(entries), wins[board.Owner_PLAYER1], wins[board.Owner_PLAYER2], wins[board.Owner_NONE], float64(moves)/float64(len(entries)))
+	} else {
+		fmt.Println("no games found")
+	}
+}
+
+// splits a flag's comma separated list, ignoring spaces
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,457 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"uttt/pkg/board"
+	"uttt/pkg/record"
+)
+
+// ========== Game Database ==========
+// A database is a file of game records, as written by record.Record.Write,
+// along with an index next to it holding a line per game with what
+// queries look at. The records stay readable by anything that reads
+// game records, like `uttt replay`, and the index is caught up with
+// them whenever it's read, so games appended without it aren't lost.
+
+// the number of moves of each game kept in the index for matching openings
+const INDEX_MOVES = 10
+
+// the extension of the index file
+const INDEX_EXT = ".idx"
+
+// DB is a game database stored in the file at its path
+type DB struct {
+	path string
+
+	// what the index held the last time it was read or written, so that
+	// appending doesn't read it again unless something else changed it:
+	// whether or not it was read, its size, the number of games in it
+	// and where the last one ends in the file
+	loaded    bool
+	indexSize int64
+	games     int
+	indexed   int64
+}
+
+// opens the database stored in the file at path; it's created once
+// the first game is appended
+func Open(path string) *DB {
+	return &DB{path: path}
+}
+
+// Entry is the indexed part of a game in the database
+type Entry struct {
+	// the game's number, counting from 1 in the order games were added
+	Game int
+	// where the game's record is in the file
+	Offset, Length int64
+
+	Player1, Player2 string
+	Date             string
+	Rules            string
+	// the size's tag, see board.Size.Tag
+	Board   string
+	Opening string
+	Result  board.Result
+	Winner  board.Owner
+	// the number of moves made
+	Moves int
+	// the first INDEX_MOVES moves, see board.Size.MoveString
+	Start []string
+}
+
+// converts the record stored at offset to an entry
+func entryOf(rec *record.Record, game int, offset, length int64) Entry {
+	e := Entry{
+		Game: game, Offset: offset, Length: length,
+		Player1: rec.Player1, Player2: rec.Player2, Date: rec.Date.Format(record.DATE_FORMAT),
+		Rules: rec.Rules.Name(), Board: rec.Size.Tag(), Opening: rec.Opening.String(),
+		Result: rec.Result, Winner: rec.Winner, Moves: len(rec.Moves),
+	}
+	for _, m := range rec.Moves {
+		if len(e.Start) == INDEX_MOVES {
+			break
+		}
+		e.Start = append(e.Start, rec.Size.MoveString(m))
+	}
+	return e
+}
+
+// names can't hold the tabs and newlines that split the index
+func clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// the entry as a line of the index
+func (e Entry) line() string {
+	fields := []string{
+		strconv.FormatInt(e.Offset, 10), strconv.FormatInt(e.Length, 10),
+		clean(e.Player1), clean(e.Player2), e.Date, e.Rules, e.Board, e.Opening,
+		e.Result.String(), e.Winner.String(), strconv.Itoa(e.Moves), strings.Join(e.Start, ","),
+	}
+	return strings.Join(fields, "\t") + "\n"
+}
+
+// reads a line of the index
+func parseEntry(line string, game int) (Entry, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 12 {
+		return Entry{}, fmt.Errorf("expected 12 fields, got %d", len(fields))
+	}
+	e := Entry{Game: game, Player1: fields[2], Player2: fields[3], Date: fields[4], Rules: fields[5], Board: fields[6], Opening: fields[7]}
+	var err1, err2, err3 error
+	e.Offset, err1 = strconv.ParseInt(fields[0], 10, 64)
+	e.Length, err2 = strconv.ParseInt(fields[1], 10, 64)
+	e.Moves, err3 = strconv.Atoi(fields[10])
+	result, ok1 := board.Result_value[fields[8]]
+	winner, ok2 := board.Owner_value[fields[9]]
+	if err1 != nil || err2 != nil || err3 != nil || !ok1 || !ok2 {
+		return Entry{}, fmt.Errorf("invalid entry %q", line)
+	}
+	e.Result, e.Winner = board.Result(result), board.Owner(winner)
+	if fields[11] != "" {
+		e.Start = strings.Split(fields[11], ",")
+	}
+	return e, nil
+}
+
+// ========== Reading and Writing ==========
+
+// Append adds the game's record to the database and indexes it
+func (db *DB) Append(rec *record.Record) error {
+	// catch the index up first so that the new game gets the right number
+	if err := db.catchUp(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := rec.Write(&buf); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(db.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	e := entryOf(rec, db.games+1, info.Size(), int64(buf.Len()))
+	return db.appendIndex([]Entry{e})
+}
+
+// indexes the games added to the file since the index was last read
+// or written. The whole index is only read again if it changed since
+func (db *DB) catchUp() error {
+	if info, err := os.Stat(db.path + INDEX_EXT); !db.loaded || err != nil || info.Size() != db.indexSize {
+		_, err := db.Index()
+		return err
+	}
+	added, err := db.scan(db.indexed, db.games)
+	if err != nil || len(added) == 0 {
+		return err
+	}
+	return db.appendIndex(added)
+}
+
+// appends the entries to the index
+func (db *DB) appendIndex(entries []Entry) error {
+	f, err := os.OpenFile(db.path+INDEX_EXT, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	for _, e := range entries {
+		w.WriteString(e.line())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	last := entries[len(entries)-1]
+	return db.remember(f, last.Game, last.Offset+last.Length)
+}
+
+// keeps what the index holds, after writing to or reading it through f
+func (db *DB) remember(f *os.File, games int, indexed int64) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	db.loaded, db.indexSize, db.games, db.indexed = true, info.Size(), games, indexed
+	return nil
+}
+
+// Index returns the entry of every game in the database, in order.
+// Games in the file that aren't indexed yet get indexed first
+func (db *DB) Index() ([]Entry, error) {
+	var entries []Entry
+	var indexed int64
+	f, err := os.Open(db.path + INDEX_EXT)
+	switch {
+	case err == nil:
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			e, err := parseEntry(scanner.Text(), len(entries)+1)
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s line %d: %w", db.path+INDEX_EXT, len(entries)+1, err)
+			}
+			entries = append(entries, e)
+			indexed = e.Offset + e.Length
+		}
+		if err := scanner.Err(); err != nil {
+			f.Close()
+			return nil, err
+		}
+		err = db.remember(f, len(entries), indexed)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	case os.IsNotExist(err):
+		db.loaded, db.indexSize, db.games, db.indexed = true, 0, 0, 0
+	default:
+		return nil, err
+	}
+
+	added, err := db.scan(indexed, len(entries))
+	if err != nil || len(added) == 0 {
+		return entries, err
+	}
+	if err := db.appendIndex(added); err != nil {
+		return nil, err
+	}
+	return append(entries, added...), nil
+}
+
+// reads the entries of the games in the file after offset, where
+// games is the number of games before them
+func (db *DB) scan(offset int64, games int) ([]Entry, error) {
+	f, err := os.Open(db.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case offset == info.Size():
+		return nil, nil
+	case offset > info.Size():
+		return nil, fmt.Errorf("%s is shorter than its index; delete %s to rebuild it", db.path, db.path+INDEX_EXT)
+	}
+	// only what comes after offset is read
+	data := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(data, offset); err != nil {
+		return nil, err
+	}
+
+	// every game starts with a header right after a blank line
+	// (or the start of the file), following its moves
+	var starts []int64
+	prevBlank, inMoves := true, false
+	for pos := int64(0); pos < int64(len(data)); {
+		end := pos + int64(bytes.IndexByte(data[pos:], '\n')) + 1
+		if end == pos {
+			end = int64(len(data))
+		}
+		line := strings.TrimSpace(string(data[pos:end]))
+		if strings.HasPrefix(line, "[") && (len(starts) == 0 || inMoves) && prevBlank {
+			starts = append(starts, pos)
+			inMoves = false
+		} else if line != "" && !strings.HasPrefix(line, "[") {
+			inMoves = true
+		}
+		prevBlank = line == ""
+		pos = end
+	}
+
+	var entries []Entry
+	for i, start := range starts {
+		end := int64(len(data))
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		recs, err := record.Parse(bytes.NewReader(data[start:end]))
+		if err != nil {
+			return nil, fmt.Errorf("%s game %d: %w", db.path, games+i+1, err)
+		}
+		if len(recs) != 1 {
+			return nil, fmt.Errorf("%s game %d: expected a game at offset %d", db.path, games+i+1, offset+start)
+		}
+		entries = append(entries, entryOf(recs[0], games+i+1, offset+start, end-start))
+	}
+	return entries, nil
+}
+
+// Record reads the record of the entry's game
+func (db *DB) Record(e Entry) (*record.Record, error) {
+	f, err := os.Open(db.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	recs, err := record.Parse(io.NewSectionReader(f, e.Offset, e.Length))
+	if err != nil {
+		return nil, fmt.Errorf("game %d: %w", e.Game, err)
+	}
+	if len(recs) != 1 {
+		return nil, fmt.Errorf("game %d: expected a game at offset %d", e.Game, e.Offset)
+	}
+	return recs[0], nil
+}
+
+// WriteStream writes the records of the entries' games to w as a
+// stream of GameRecord messages, see record.StreamWriter
+func (db *DB) WriteStream(w io.Writer, entries []Entry) error {
+	sw := record.NewStreamWriter(w)
+	for _, e := range entries {
+		rec, err := db.Record(e)
+		if err != nil {
+			return err
+		}
+		if err := sw.Write(rec.ToProto()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ========== Queries ==========
+
+// Query picks games by their entries. Fields left at their zero value match every game
+type Query struct {
+	// the winners to match; NONE matches games nobody won
+	Winners []board.Owner
+	// how the game ended, e.g. Result_RESIGNATION
+	Results []board.Result
+	// the fewest and most moves made; MaxMoves 0 means no limit
+	MinMoves, MaxMoves int
+	// a name either player had
+	Player           string
+	Player1, Player2 string
+	Rules            string
+	// the size's tag, see board.Size.Tag
+	Board string
+	// where the first move could be made, see board.Opening.String
+	Opening string
+	// the moves the game started with, see board.Size.MoveString
+	Start []string
+}
+
+// whether or not the entry's game matches the query, as far as the
+// entry tells. Openings longer than the entry's Start need the record
+// to be checked too; see Find
+func (q Query) Match(e Entry) bool {
+	if len(q.Winners) > 0 && !hasWinner(q.Winners, e.Winner) {
+		return false
+	}
+	if len(q.Results) > 0 && !hasResult(q.Results, e.Result) {
+		return false
+	}
+	if e.Moves < q.MinMoves || (q.MaxMoves > 0 && e.Moves > q.MaxMoves) {
+		return false
+	}
+	if q.Player != "" && e.Player1 != q.Player && e.Player2 != q.Player {
+		return false
+	}
+	if (q.Player1 != "" && e.Player1 != q.Player1) || (q.Player2 != "" && e.Player2 != q.Player2) {
+		return false
+	}
+	if q.Rules != "" && e.Rules != q.Rules {
+		return false
+	}
+	if (q.Board != "" && e.Board != q.Board) || (q.Opening != "" && e.Opening != q.Opening) {
+		return false
+	}
+	if len(q.Start) > e.Moves {
+		return false
+	}
+	for i := 0; i < len(q.Start) && i < len(e.Start); i++ {
+		if q.Start[i] != e.Start[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasWinner(winners []board.Owner, winner board.Owner) bool {
+	for _, w := range winners {
+		if w == winner {
+			return true
+		}
+	}
+	return false
+}
+func hasResult(results []board.Result, result board.Result) bool {
+	for _, r := range results {
+		if r == result {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the entries of the games matching the query, in order.
+// limit caps how many are returned; 0 means no limit
+func (db *DB) Find(q Query, limit int) ([]Entry, error) {
+	entries, err := db.Index()
+	if err != nil {
+		return nil, err
+	}
+	var found []Entry
+	for _, e := range entries {
+		if limit > 0 && len(found) == limit {
+			break
+		}
+		if !q.Match(e) {
+			continue
+		}
+		if len(q.Start) > len(e.Start) {
+			rec, err := db.Record(e)
+			if err != nil {
+				return nil, err
+			}
+			if !startsWith(rec, q.Start) {
+				continue
+			}
+		}
+		found = append(found, e)
+	}
+	return found, nil
+}
+
+// whether or not the recorded game started with the moves
+func startsWith(rec *record.Record, start []string) bool {
+	if len(rec.Moves) < len(start) {
+		return false
+	}
+	for i, m := range start {
+		if rec.Size.MoveString(rec.Moves[i]) != m {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package db
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	"uttt/pkg/board"
+	"uttt/pkg/record"
+)
+
+// a record of n moves on the board of the given size, starting with
+// the start moves and then always making the last legal move
+func testRecord(t *testing.T, size board.Size, opening board.Opening, n int, start []string, winner board.Owner) *record.Record {
+	rec := &record.Record{
+		Player1: "p1", Player2: "p2", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Rules: board.StandardRules{}, Size: size, Opening: opening,
+		Result: board.Result_DRAW, Winner: winner,
+	}
+	switch winner {
+	case board.Owner_PLAYER1:
+		rec.Result = board.Result_PLAYER1_WIN
+	case board.Owner_PLAYER2:
+		rec.Result = board.Result_PLAYER2_WIN
+	}
+
+	g := board.NewGame(opening.NewPosition(size, rec.Rules))
+	for i := 0; i < n; i++ {
+		moves := g.Position().Moves()
+		if len(moves) == 0 {
+			t.Fatalf("the game ended after %d moves, expected %d", i, n)
+		}
+		m := moves[len(moves)-1]
+		if i < len(start) {
+			var err error
+			if m, err = size.ParseMove(start[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !g.Position().Legal(m) {
+			t.Fatalf("move %d %s isn't legal", i+1, size.MoveString(m))
+		}
+		g.Play(m)
+	}
+	rec.Moves = g.History()
+	return rec
+}
+
+// the numbers of the games the entries are of
+func gameNumbers(entries []Entry) []int {
+	var games []int
+	for _, e := range entries {
+		games = append(games, e.Game)
+	}
+	return games
+}
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.uttt")
+	db := Open(path)
+	size := board.DefaultSize()
+	recs := []*record.Record{
+		testRecord(t, size, board.FreeOpening, 12, []string{"4.4"}, board.Owner_PLAYER1),
+		testRecord(t, size, board.CenterOpening, 20, nil, board.Owner_PLAYER2),
+	}
+	for _, rec := range recs {
+		if err := db.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := Open(path).Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gameNumbers(entries), []int{1, 2}) {
+		t.Fatalf("indexed games %v, expected [1 2]", gameNumbers(entries))
+	}
+	for i, e := range entries {
+		if e.Moves != len(recs[i].Moves) || e.Opening != recs[i].Opening.String() || e.Winner != recs[i].Winner {
+			t.Errorf("game %d indexed as %+v", e.Game, e)
+		}
+		rec, err := db.Record(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(moveStrings(rec), moveStrings(recs[i])) {
+			t.Errorf("game %d read back with moves %v, expected %v", e.Game, moveStrings(rec), moveStrings(recs[i]))
+		}
+	}
+}
+
+// games appended to the file without the index get indexed once it's
+// read, and appending through a database that read the index before
+// doesn't lose them
+func TestCatchUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.uttt")
+	db := Open(path)
+	size := board.DefaultSize()
+	if err := db.Append(testRecord(t, size, board.FreeOpening, 10, nil, board.Owner_PLAYER1)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{11, 13} {
+		if err := testRecord(t, size, board.FreeOpening, n, nil, board.Owner_PLAYER2).Write(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.Close()
+
+	if err := db.Append(testRecord(t, size, board.FreeOpening, 14, nil, board.Owner_NONE)); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Open(path).Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var moves []int
+	for _, e := range entries {
+		moves = append(moves, e.Moves)
+	}
+	if !reflect.DeepEqual(gameNumbers(entries), []int{1, 2, 3, 4}) || !reflect.DeepEqual(moves, []int{10, 11, 13, 14}) {
+		t.Fatalf("indexed games %v with %v moves, expected [1 2 3 4] with [10 11 13 14]", gameNumbers(entries), moves)
+	}
+}
+
+func TestFind(t *testing.T) {
+	db := Open(filepath.Join(t.TempDir(), "games.uttt"))
+	size, large := board.DefaultSize(), board.Size{Rows: 4, Cols: 4, InARow: 3, Levels: 2}
+	for _, rec := range []*record.Record{
+		// 1: PLAYER2 wins in under 30 moves starting 4.4
+		testRecord(t, size, board.FreeOpening, 20, []string{"4.4"}, board.Owner_PLAYER2),
+		// 2: too long
+		testRecord(t, size, board.FreeOpening, 35, []string{"4.4"}, board.Owner_PLAYER2),
+		// 3: the wrong winner
+		testRecord(t, size, board.FreeOpening, 20, []string{"4.4"}, board.Owner_PLAYER1),
+		// 4: another start
+		testRecord(t, size, board.FreeOpening, 20, []string{"0.0"}, board.Owner_PLAYER2),
+		// 5: forced into the center
+		testRecord(t, size, board.CenterOpening, 20, []string{"4.4"}, board.Owner_PLAYER2),
+		// 6: a larger board
+		testRecord(t, large, board.FreeOpening, 20, nil, board.Owner_PLAYER2),
+		// 7: PLAYER2 wins in under 30 moves starting 4.4.4.0
+		testRecord(t, size, board.FreeOpening, 25, []string{"4.4", "4.0"}, board.Owner_PLAYER2),
+	} {
+		if err := db.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		games []int
+	}{
+		{"everything", Query{}, []int{1, 2, 3, 4, 5, 6, 7}},
+		{"P2 wins under 30 moves starting 4.4", Query{Winners: []board.Owner{board.Owner_PLAYER2}, MaxMoves: 29, Start: []string{"4.4"}}, []int{1, 5, 7}},
+		{"free openings", Query{Winners: []board.Owner{board.Owner_PLAYER2}, MaxMoves: 29, Start: []string{"4.4"}, Opening: "free"}, []int{1, 7}},
+		{"center openings", Query{Opening: "center"}, []int{5}},
+		{"3x3 boards", Query{Board: "3x3", Winners: []board.Owner{board.Owner_PLAYER2}, MinMoves: 25}, []int{2, 7}},
+		{"4x4 boards", Query{Board: large.Tag()}, []int{6}},
+		{"two start moves", Query{Start: []string{"4.4", "4.0"}}, []int{7}},
+	}
+	for _, test := range tests {
+		entries, err := db.Find(test.query, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if games := gameNumbers(entries); !reflect.DeepEqual(games, test.games) {
+			t.Errorf("%s: found games %v, expected %v", test.name, games, test.games)
+		}
+	}
+}
+
+// the record's moves as written in it
+func moveStrings(rec *record.Record) []string {
+	var moves []string
+	for _, m := range rec.Moves {
+		moves = append(moves, rec.Size.MoveString(m))
+	}
+	return moves
+}
+
+func TestWriteStream(t *testing.T) {
+	db := Open(filepath.Join(t.TempDir(), "games.uttt"))
+	size := board.DefaultSize()
+	recs := []*record.Record{
+		testRecord(t, size, board.FreeOpening, 10, nil, board.Owner_PLAYER1),
+		testRecord(t, size, board.CenterOpening, 15, nil, board.Owner_PLAYER2),
+	}
+	for _, rec := range recs {
+		if err := db.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := db.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := db.WriteStream(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	games, err := record.ReadStream(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != len(recs) {
+		t.Fatalf("wrote %d games, expected %d", len(games), len(recs))
+	}
+	for i, g := range games {
+		rec, err := record.FromProto(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Opening != recs[i].Opening || rec.Winner != recs[i].Winner || !reflect.DeepEqual(moveStrings(rec), moveStrings(recs[i])) {
+			t.Errorf("game %d written as %v with moves %v", i+1, g, moveStrings(rec))
+		}
+	}
+}
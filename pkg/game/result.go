@@ -0,0 +1,60 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"uttt/pkg/board"
+)
+
+// the reasons a player can stop playing before the board is decided
+var (
+	ErrResigned  = errors.New("the player resigned")
+	ErrTimeout   = errors.New("the player ran out of time")
+	ErrAbandoned = errors.New("the player left the game")
+)
+
+// Outcome is how a game ended and who won it, if anyone
+type Outcome struct {
+	Result board.Result
+	Winner board.Owner
+}
+
+// the outcome of a game decided in the position
+func boardOutcome(p *board.Position) Outcome {
+	result, winner := p.Result()
+	return Outcome{Result: result, Winner: winner}
+}
+
+// the outcome of a game that player stopped playing because of err
+func stoppedOutcome(player board.Owner, err error) Outcome {
+	switch err {
+	case ErrResigned:
+		return Outcome{Result: board.Result_RESIGNATION, Winner: player.Opponent()}
+	case ErrTimeout:
+		return Outcome{Result: board.Result_TIMEOUT, Winner: player.Opponent()}
+	}
+	return Outcome{Result: board.Result_ABANDONED}
+}
+
+// the outcome of a game player forfeited by making too many invalid moves
+func forfeitOutcome(player board.Owner) Outcome {
+	return Outcome{Result: board.Result_FORFEIT, Winner: player.Opponent()}
+}
+
+func (o Outcome) String() string {
+	switch o.Result {
+	case board.Result_PLAYER1_WIN, board.Result_PLAYER2_WIN:
+		return fmt.Sprintf("%v won", o.Winner)
+	case board.Result_DRAW:
+		return "draw"
+	case board.Result_RESIGNATION:
+		return fmt.Sprintf("%v won by resignation", o.Winner)
+	case board.Result_TIMEOUT:
+		return fmt.Sprintf("%v won on time", o.Winner)
+	case board.Result_FORFEIT:
+		return fmt.Sprintf("%v won by forfeit", o.Winner)
+	case board.Result_ABANDONED:
+		return "game abandoned"
+	}
+	return "game ongoing"
+}
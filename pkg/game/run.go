@@ -1,24 +1,63 @@
 package game
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
+	"syscall"
 	"time"
 	"uttt/pkg/board"
+	"uttt/pkg/db"
+	"uttt/pkg/record"
 
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 type Runner struct {
-	turn      bool
-	gameboard *board.Board
+	game *board.Game
+	// the position games start from; nil to start from Opening
+	start *board.Position
+	// when each move of the game was made and how long it took
+	times []record.MoveTime
+	// the game the next game picks up from, and the times of its moves;
+	// see Resume
+	resumed      *board.Game
+	resumedTimes []record.MoveTime
+	// collects training samples when SamplePath is set
+	samples *sampleWriter
+	// the database games are added to when RecordPath is set
+	records *db.DB
+
+	// the shape of the board the games are played on
+	Size board.Size
+	// the rules the games are played by
+	Rules board.Rules
+	// where the first move of each game can be made
+	Opening board.Opening
+	// how long AI players get to make each move; 0 means forever
+	MoveTimeout time.Duration
+	// how many invalid moves in a row forfeit the game; 0 means unlimited
+	MaxInvalidMoves int
+	// the game database (see pkg/db) every game is appended to;
+	// empty to not record games
+	RecordPath string
+	// the directory training samples of every move are written to as
+	// .npz shards (see samples.go); empty to not write samples
+	SamplePath string
+	// the number of samples in each shard; 0 means DEFAULT_SHARD_SIZE
+	ShardSize int
 }
 
 func NewRunner() *Runner {
-	return &Runner{turn: true, gameboard: board.NewProtoBoard()}
+	size := board.DefaultSize()
+	return &Runner{game: board.NewGame(board.NewPosition(size, board.StandardRules{})), Size: size, Rules: board.StandardRules{}, Opening: board.FreeOpening}
 }
 
 // =======================================================
@@ -26,21 +65,27 @@ func NewRunner() *Runner {
 // =======================================================
 
 type Player interface {
+	// name is what the player is called in game records
+	name() string
+
 	// getMove returns:
 	//     - *board.Move - the move to make
-	//     - bool - whether or not the user requested to quit;
-	//            true if yes, false if no
-	getMove() (*board.Move, bool)
+	//     - error - why the player stopped playing (ErrResigned,
+	//            ErrTimeout or ErrAbandoned); nil if they didn't
+	getMove() (*board.Move, error)
 
 	// displayBoard parameters:
-	//     - *board.Board - the current board
+	//     - *board.Position - the current position
 	//     - *board.Owner - whose turn it is currently (PLAYER1 or PLAYER2)
-	displayBoard(*board.Board, *board.Owner)
+	displayBoard(*board.Position, *board.Owner)
 
 	// afterMove parameters:
-	//     - *board.Board - the (changed) board
-	//     - bool - whether or not the previous move was valid
-	afterMove(*board.Board, bool)
+	//     - *board.Position - the (changed) position
+	//     - *board.MoveResult - what the previous move changed;
+	//            nil if it was rejected
+	//     - error - why the previous move was rejected;
+	//            nil if it was valid
+	afterMove(*board.Position, *board.MoveResult, error)
 }
 
 // =========== TerminalPlayer ===========
@@ -52,43 +97,56 @@ type TerminalPlayer struct {
 func NewTerminalPlayer(runner *Runner) *TerminalPlayer {
 	return &TerminalPlayer{runner: runner}
 }
-func (t *TerminalPlayer) getMove() (*board.Move, bool) {
+func (t *TerminalPlayer) name() string {
+	return "human"
+}
+func (t *TerminalPlayer) getMove() (*board.Move, error) {
 	return t.runner.getMoveTerminal()
 }
-func (t *TerminalPlayer) displayBoard(b *board.Board, player *board.Owner) {
+func (t *TerminalPlayer) displayBoard(p *board.Position, player *board.Owner) {
 	// print messages
 	fmt.Printf("%v's turn:\n", *player)
-	fmt.Println(b.TerminalString())
+	fmt.Println(p.TerminalString())
 }
-func (t *TerminalPlayer) afterMove(_ *board.Board, prevValid bool) {
-	if !prevValid {
-		fmt.Println("invalid move!!!")
+func (t *TerminalPlayer) afterMove(_ *board.Position, _ *board.MoveResult, err error) {
+	if err != nil {
+		fmt.Println("invalid move!!!", err)
 	}
 }
 
 // =========== AIPlayer ===========
-// represents an AI that communicates via protocol buffers
+// represents an AI that communicates via protocol buffers. Every
+// message on the sockets is preceded by its size as a varint, so that
+// messages of large boards can span reads
 type NetResources struct {
 	stateConn, actionConn, returnConn net.Conn
+	// buffers the action connection for reading sizes
+	actions *bufio.Reader
 }
 type AIPlayer struct {
+	runner *Runner
 	player board.Owner
 	nr     *NetResources
 }
 
+// waits for an AI to connect to each port. The listeners are closed
+// once it has, so that the ports can be listened on again for the next AI
 func NewNetResources() *NetResources {
 	sListener, err := net.Listen("tcp", "localhost:"+board.STATE_PORT)
 	if err != nil {
 		log.Fatalln("failed to listen on state port")
 	}
+	defer sListener.Close()
 	aListener, err := net.Listen("tcp", "localhost:"+board.ACTION_PORT)
 	if err != nil {
 		log.Fatalln("failed to listen on action port")
 	}
+	defer aListener.Close()
 	rListener, err := net.Listen("tcp", "localhost:"+board.RETURN_PORT)
 	if err != nil {
 		log.Fatalln("failed to listen on return port")
 	}
+	defer rListener.Close()
 
 	sConn, err := sListener.Accept()
 	if err != nil {
@@ -103,176 +161,404 @@ func NewNetResources() *NetResources {
 		log.Fatalln("failed to accept return connection")
 	}
 
-	return &NetResources{stateConn: sConn, actionConn: aConn, returnConn: rConn}
+	return &NetResources{stateConn: sConn, actionConn: aConn, returnConn: rConn, actions: bufio.NewReader(aConn)}
 }
-func NewAIPlayer(player_num board.Owner, nr *NetResources) *AIPlayer {
-	return &AIPlayer{player: player_num, nr: nr}
+func NewAIPlayer(runner *Runner, player_num board.Owner, nr *NetResources) *AIPlayer {
+	return &AIPlayer{runner: runner, player: player_num, nr: nr}
 }
 func write(m protoreflect.ProtoMessage, con net.Conn) {
-	bytes, err := proto.Marshal(m)
-	if err != nil {
-		log.Fatalln("Failed to encode State Message")
-	}
-
-	// write the bytes
-	if _, err := con.Write(bytes); err != nil {
-		log.Fatalln("failed to write bytes to socket")
+	// write the size, then the bytes
+	if _, err := protodelim.MarshalTo(con, m); err != nil {
+		log.Fatalln("failed to write message to socket with error: ", err.Error())
 	}
 }
-func (a *AIPlayer) getStateMessage(b *board.Board, player *board.Owner) *board.StateMessage {
-	owners := make([]board.Owner, 9)
-	for i := 0; i < board.CELLS; i++ {
-		owners[i] = b.Get(board.ToCoord(uint32(i))).Owner()
-	}
-	winner := b.Owner()
-	done := winner != board.Owner_NONE || b.Full()
-	return &board.StateMessage{Board: b, Cellowners: owners, Turn: *player, Winner: winner, Done: done, Validmoves: b.Moves()}
+
+// converts the position to the message sent to the AI; this is the
+// only place positions become protobuf boards
+func (a *AIPlayer) getStateMessage(p *board.Position, player *board.Owner) *board.StateMessage {
+	result, winner := p.Result()
+	done := result != board.Result_ONGOING
+	return &board.StateMessage{Board: p.ToProto(), Cellowners: p.CellOwners(), Turn: *player, Winner: winner, Done: done, Validmoves: p.Moves(), Result: result, Rules: p.Rules().Name()}
 }
 
-func (a *AIPlayer) displayBoard(b *board.Board, player *board.Owner) {
-	write(a.getStateMessage(b, player), a.nr.stateConn)
+func (a *AIPlayer) displayBoard(p *board.Position, player *board.Owner) {
+	write(a.getStateMessage(p, player), a.nr.stateConn)
 }
-func (a *AIPlayer) afterMove(b *board.Board, prevValid bool) {
-	ret := board.ReturnMessage{State: a.getStateMessage(b, &a.player), Valid: prevValid}
+func (a *AIPlayer) afterMove(p *board.Position, result *board.MoveResult, err error) {
+	ret := board.ReturnMessage{State: a.getStateMessage(p, &a.player), Valid: err == nil, Reason: reasonOf(err)}
+	if result != nil {
+		ret.Result = result.ToProto()
+	}
 	write(&ret, a.nr.returnConn)
 }
-func (a *AIPlayer) getMove() (*board.Move, bool) {
-	// open content
-	bytes := make([]byte, board.MAX_MSG_SIZE)
-	n, err := a.nr.actionConn.Read(bytes)
-	if err != nil {
-		log.Fatalln("failed to read in action with error: ", err.Error())
+func (a *AIPlayer) name() string {
+	return "ai"
+}
+func (a *AIPlayer) getMove() (*board.Move, error) {
+	// a zero deadline means no deadline
+	var deadline time.Time
+	if a.runner.MoveTimeout > 0 {
+		deadline = time.Now().Add(a.runner.MoveTimeout)
+	}
+	if err := a.nr.actionConn.SetReadDeadline(deadline); err != nil {
+		log.Fatalln("failed to set action deadline with error: ", err.Error())
 	}
 
+	// open content
 	message := &board.ActionMessage{}
-	err = proto.Unmarshal(bytes[:n], message)
+	err := protodelim.UnmarshalOptions{MaxSize: board.MAX_MSG_SIZE}.UnmarshalFrom(a.nr.actions, message)
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return nil, ErrTimeout
+	}
 	if err != nil {
-		log.Fatalln("failed to decode action")
+		log.Fatalln("failed to read in action with error: ", err.Error())
 	}
 
 	// assume that the an invalid coordinate means that the
-	// ai / computer quit
-	return message.Move, !message.Move.Large.Valid() || !message.Move.Small.Valid()
+	// ai / computer resigned
+	if !message.Move.GetLarge().Valid() || !message.Move.GetSmall().Valid() {
+		return nil, ErrResigned
+	}
+	return message.Move, nil
 }
 
 // =======================================================
 // =========== Terminal Helpers ===========
 // =======================================================
 
-func getCoord(where string) (c *board.Coord, quit bool) {
-	fmt.Println("Where r u going (0 - 8)", where)
+func getCoord(size board.Size, where string) (c *board.Coord, err error) {
+	fmt.Printf("Where r u going (0 - %d, q to quit, r to resign) %s\n", size.Cells()-1, where)
 	var inp string
 	fmt.Scanln(&inp)
 
 	// process input
-	quit = inp == "q"
-	num, _ := strconv.ParseInt(inp, 10, 8)
-	c = board.ToCoord(uint32(num))
+	switch inp {
+	case "q":
+		return nil, ErrAbandoned
+	case "r":
+		return nil, ErrResigned
+	}
+	num, parseErr := strconv.ParseInt(inp, 10, 32)
+	if parseErr != nil || num < 0 {
+		// anything that isn't a number is out of range
+		c = &board.Coord{}
+		c.Invalidate()
+		return
+	}
+	c = size.Coord(uint32(num))
 	return
 }
 
-func (runner *Runner) getMoveTerminal() (move *board.Move, quit bool) {
-	move = &board.Move{}
-	if !runner.gameboard.CurCell.Valid() {
-		move.Large, quit = getCoord("in large cells")
-		if quit {
-			return
+func (runner *Runner) getMoveTerminal() (*board.Move, error) {
+	// only ask for the cells the move isn't already forced into
+	p := runner.game.Position()
+	size := p.Size()
+	path := p.Target()
+	for len(path) < size.Levels {
+		where := fmt.Sprintf("in level %d cells", len(path)+1)
+		switch len(path) {
+		case 0:
+			where = "in large cells"
+		case size.Levels - 1:
+			where = "in small cells"
 		}
-	} else {
-		move.Large = &board.Coord{Row: runner.gameboard.CurCell.Row, Col: runner.gameboard.CurCell.Col}
-	}
 
-	move.Small, quit = getCoord("in small cells")
-	return
+		c, err := getCoord(size, where)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, c)
+	}
+	return board.NewMove(path...), nil
 }
 
 // =======================================================
 // =========== Run Section ===========
 // =======================================================
 
-func (runner *Runner) run(player1, player2 Player) {
+// whether or not the current game is still being played
+func (runner *Runner) ongoing() bool {
+	result, _ := runner.game.Position().Result()
+	return result == board.Result_ONGOING
+}
+
+// SetStart makes games start from the position instead of an empty
+// board. Positions supplied by clients or files can be anything, so
+// it's rejected with every problem board.Check finds
+func (runner *Runner) SetStart(p *board.Position) error {
+	if err := board.Check(p); err != nil {
+		return fmt.Errorf("invalid start position: %w", err)
+	}
+	runner.start = p.Clone()
+	return nil
+}
+
+// StartPosition returns the position games start from: the one given
+// to SetStart, or else an empty board following Opening
+func (runner *Runner) StartPosition() *board.Position {
+	if runner.start != nil {
+		return runner.start.Clone()
+	}
+	return runner.Opening.NewPosition(runner.Size, runner.Rules)
+}
+
+// Resume makes the next game pick up where the recorded game left
+// off, playing by its size, rules and opening. The record has to
+// replay to a position that's still being played
+func (runner *Runner) Resume(rec *record.Record) error {
+	g, err := rec.Game()
+	if err != nil {
+		return err
+	}
+	if result, _ := g.Position().Result(); result != board.Result_ONGOING {
+		return fmt.Errorf("the game is already over: %v", result)
+	}
+	runner.start = nil
+	if rec.Position != "" {
+		if err := runner.SetStart(g.Start()); err != nil {
+			return err
+		}
+	}
+	runner.Size, runner.Rules, runner.Opening = rec.Size, rec.Rules, rec.Opening
+	runner.resumed, runner.resumedTimes = g, rec.Times
+	return nil
+}
+
+// sets up the position for a new game
+func (runner *Runner) newGame() {
+	if runner.resumed != nil {
+		runner.game, runner.resumed = runner.resumed, nil
+		runner.times = make([]record.MoveTime, len(runner.game.History()))
+		copy(runner.times, runner.resumedTimes)
+		runner.resumedTimes = nil
+		return
+	}
+	runner.game = board.NewGame(runner.StartPosition())
+	runner.times = nil
+}
+
+// run plays a new game between the two players and returns how it ended
+func (runner *Runner) run(player1, player2 Player) (outcome Outcome) {
 	//fmt.Println("playing Ultimate Tic-Tac-Toe")
+	runner.newGame()
+	if runner.SamplePath != "" && runner.samples == nil {
+		var err error
+		if runner.samples, err = newSampleWriter(runner.SamplePath, runner.ShardSize, runner.game.Position().Size()); err != nil {
+			log.Println("failed to write training samples:", err)
+			runner.SamplePath = ""
+		}
+	}
 
 	var curPlayer Player
-	for runner.gameboard.Owner() == board.Owner_NONE && !runner.gameboard.Full() {
+	var stopped error
+	invalid := 0
+	// when the player to move was first asked for it, so that a move's
+	// think time includes the invalid moves before it
+	asked := time.Now()
+	for runner.ongoing() {
 		// get the turn number
-		var playerNum board.Owner
-		if runner.turn {
-			playerNum = board.Owner_PLAYER1
+		p := runner.game.Position()
+		playerNum := p.Turn()
+		if playerNum == board.Owner_PLAYER1 {
 			curPlayer = player1
 		} else {
-			playerNum = board.Owner_PLAYER2
 			curPlayer = player2
 		}
 
-		curPlayer.displayBoard(runner.gameboard, &playerNum)
+		curPlayer.displayBoard(p, &playerNum)
 
-		move, quit := curPlayer.getMove()
-		if quit {
+		move, err := curPlayer.getMove()
+		if err != nil {
+			outcome = stoppedOutcome(playerNum, err)
+			stopped = err
 			break
 		}
 
 		// validate move
-		if validateMove(runner.gameboard, move) {
-			runner.gameboard.Get(move.Large).(*board.Cell).Get(move.Small).(*board.Space).Val = playerNum
-
-			// go to the next space
-			if validateCell(runner.gameboard, move.Small) {
-				runner.gameboard.CurCell.Row, runner.gameboard.CurCell.Col = move.Small.Row, move.Small.Col
-			} else {
-				runner.gameboard.CurCell.Invalidate()
+		if err := validateMove(p, move); err == nil {
+			if runner.samples != nil {
+				runner.samples.add(p, move)
 			}
-
-			// change turn
-			runner.turn = !runner.turn
-			curPlayer.afterMove(runner.gameboard, true)
+			// also changes the turn
+			result := runner.game.Play(move)
+			now := time.Now()
+			runner.times = append(runner.times, record.MoveTime{At: now, Think: now.Sub(asked)})
+			asked = now
+			invalid = 0
+			curPlayer.afterMove(p, &result, nil)
 		} else {
-			curPlayer.afterMove(runner.gameboard, false)
+			invalid++
+			curPlayer.afterMove(p, nil, err)
+			if runner.MaxInvalidMoves > 0 && invalid >= runner.MaxInvalidMoves {
+				outcome = forfeitOutcome(playerNum)
+				break
+			}
 		}
 	}
+	if outcome.Result == board.Result_ONGOING {
+		outcome = boardOutcome(runner.game.Position())
+	}
 
 	// check if either player was a terminal player
 	// if so, print out final message
 	_, valid1 := player1.(*TerminalPlayer)
 	_, valid2 := player2.(*TerminalPlayer)
+	if runner.samples != nil {
+		if err := runner.samples.finish(outcome); err != nil {
+			log.Println("failed to write training samples:", err)
+		}
+	}
+	// someone quitting at the terminal can pick the game up later. Saved
+	// games go to the database once they're resumed and finished, so
+	// that they aren't counted twice
+	saved := false
+	if _, terminal := curPlayer.(*TerminalPlayer); terminal && stopped == ErrAbandoned {
+		saved = runner.offerSave(player1, player2)
+	}
+	if runner.RecordPath != "" && !saved {
+		if runner.records == nil {
+			runner.records = db.Open(runner.RecordPath)
+		}
+		if err := runner.records.Append(runner.record(player1, player2, outcome)); err != nil {
+			log.Println("failed to save game record:", err)
+		}
+	}
 	if valid1 || valid2 {
-		fmt.Println(runner.gameboard.TerminalString())
-		fmt.Printf("%v won\n", runner.gameboard.Owner())
+		fmt.Println(runner.game.Position().TerminalString())
+		fmt.Println(outcome)
 	}
+	return
 }
 
-func (runner *Runner) RunPVP() {
-	runner.run(NewTerminalPlayer(runner), NewTerminalPlayer(runner))
+// the record of the game that just ended
+func (runner *Runner) record(player1, player2 Player, outcome Outcome) *record.Record {
+	rec := &record.Record{
+		Player1:     player1.name(),
+		Player2:     player2.name(),
+		Date:        time.Now(),
+		Rules:       runner.Rules,
+		Size:        runner.Size,
+		Opening:     runner.Opening,
+		TimeControl: runner.MoveTimeout,
+		Result:      outcome.Result,
+		Winner:      outcome.Winner,
+		Moves:       runner.game.History(),
+		Times:       runner.times,
+	}
+	if runner.start != nil {
+		rec.Position = runner.start.Notation()
+	}
+	return rec
 }
-func (runner *Runner) RunPVAI() {
+
+// asks for a file to save the abandoned game to, so that it can be
+// resumed with --resume, and returns whether or not it was saved
+func (runner *Runner) offerSave(player1, player2 Player) bool {
+	for {
+		fmt.Println("Save the game to continue it later with --resume? Enter a file name, or nothing to not save")
+		var path string
+		fmt.Scanln(&path)
+		if path == "" {
+			return false
+		}
+		err := runner.save(path, runner.record(player1, player2, Outcome{Result: board.Result_ONGOING}))
+		if err == nil {
+			fmt.Printf("saved; continue with --resume %s\n", path)
+			return true
+		}
+		fmt.Println("failed to save the game:", err)
+	}
+}
+
+// appends the unfinished game's record to the file at path. It can't
+// be the game database, which only holds games once they're over
+func (runner *Runner) save(path string, rec *record.Record) error {
+	if runner.RecordPath != "" {
+		abs, err1 := filepath.Abs(path)
+		records, err2 := filepath.Abs(runner.RecordPath)
+		if err1 == nil && err2 == nil && abs == records {
+			return fmt.Errorf("%s is the game database, pick another file", path)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	err = rec.Write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writes the training samples that didn't fill a shard yet
+func (runner *Runner) flushSamples() {
+	if runner.samples == nil {
+		return
+	}
+	if err := runner.samples.flush(); err != nil {
+		log.Println("failed to write training samples:", err)
+	}
+}
+
+func (runner *Runner) RunPVP() Outcome {
+	outcome := runner.run(NewTerminalPlayer(runner), NewTerminalPlayer(runner))
+	runner.flushSamples()
+	return outcome
+}
+func (runner *Runner) RunPVAI() Outcome {
 	nr := NewNetResources()
-	runner.run(NewTerminalPlayer(runner), NewAIPlayer(board.Owner_PLAYER2, nr))
+	outcome := runner.run(NewTerminalPlayer(runner), NewAIPlayer(runner, board.Owner_PLAYER2, nr))
+	runner.flushSamples()
 
 	time.Sleep(1 * time.Second)
 	nr.actionConn.Close()
 	nr.stateConn.Close()
 	nr.returnConn.Close()
+	return outcome
 }
-func (runner *Runner) RunAIVP() {
+func (runner *Runner) RunAIVP() Outcome {
 	nr := NewNetResources()
-	runner.run(NewAIPlayer(board.Owner_PLAYER2, nr), NewTerminalPlayer(runner))
+	outcome := runner.run(NewAIPlayer(runner, board.Owner_PLAYER1, nr), NewTerminalPlayer(runner))
+	runner.flushSamples()
 
 	time.Sleep(1 * time.Second)
 	nr.actionConn.Close()
 	nr.stateConn.Close()
 	nr.returnConn.Close()
+	return outcome
 }
+
+// RunAIs plays games between AIs until the program is interrupted.
+// When training samples are written, the first interrupt stops once
+// the game being played is over, so that the samples of the games
+// played so far get written; a second one stops right away
 func (runner *Runner) RunAIs() {
 	nr := NewNetResources()
 
-	for {
-		runner.run(NewAIPlayer(board.Owner_PLAYER1, nr), NewAIPlayer(board.Owner_PLAYER2, nr))
+	stopping := make(chan struct{})
+	if runner.SamplePath != "" {
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-interrupts
+			signal.Stop(interrupts)
+			fmt.Println("stopping after this game; interrupt again to stop now")
+			close(stopping)
+		}()
+	}
 
-		// reset vars
-		runner.gameboard = board.NewProtoBoard()
-		runner.turn = true
+	for {
+		runner.run(NewAIPlayer(runner, board.Owner_PLAYER1, nr), NewAIPlayer(runner, board.Owner_PLAYER2, nr))
+		select {
+		case <-stopping:
+			runner.flushSamples()
+			nr.actionConn.Close()
+			nr.stateConn.Close()
+			nr.returnConn.Close()
+			return
+		default:
+		}
 	}
-	// nr.actionConn.Close()
-	// nr.stateConn.Close()
-	// nr.returnConn.Close()
 }
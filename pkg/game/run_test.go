@@ -0,0 +1,159 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"uttt/pkg/board"
+	"uttt/pkg/record"
+)
+
+// plays n moves of the runner's game, always making the first legal
+// move; all of them if n is -1
+func playFirstMoves(t *testing.T, runner *Runner, n int) {
+	for i := 0; i != n; i++ {
+		moves := runner.game.Position().Moves()
+		if len(moves) == 0 {
+			if n == -1 {
+				return
+			}
+			t.Fatalf("the game ended after %d moves, expected %d", i, n)
+		}
+		runner.game.Play(moves[0])
+	}
+}
+
+// saves the runner's unfinished game to a new file and reads it back
+func saveAndRead(t *testing.T, runner *Runner) *record.Record {
+	path := filepath.Join(t.TempDir(), "saved.uttt")
+	player := NewTerminalPlayer(runner)
+	if err := runner.save(path, runner.record(player, player, Outcome{Result: board.Result_ONGOING})); err != nil {
+		t.Fatal(err)
+	}
+	records, err := record.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("read %d games from %s, expected 1", len(records), path)
+	}
+	return records[0]
+}
+
+func TestResume(t *testing.T) {
+	runner := NewRunner()
+	runner.Rules, runner.Opening = board.MisereRules{}, board.CenterOpening
+	runner.Size = board.Size{Rows: 4, Cols: 4, InARow: 3, Levels: 2}
+	runner.newGame()
+	playFirstMoves(t, runner, 7)
+	rec := saveAndRead(t, runner)
+
+	resumed := NewRunner()
+	if err := resumed.Resume(rec); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Size != runner.Size || resumed.Rules.Name() != runner.Rules.Name() || resumed.Opening != runner.Opening {
+		t.Fatalf("resumed on %v under %s rules with opening %v", resumed.Size, resumed.Rules.Name(), resumed.Opening)
+	}
+	resumed.newGame()
+	if got, want := resumed.game.Position().Notation(), runner.game.Position().Notation(); got != want {
+		t.Errorf("resumed at %s, expected %s", got, want)
+	}
+	if len(resumed.game.History()) != 7 {
+		t.Errorf("resumed with %d moves, expected 7", len(resumed.game.History()))
+	}
+
+	// only the next game picks up the saved one
+	resumed.newGame()
+	if got, want := resumed.game.Position().Notation(), runner.StartPosition().Notation(); got != want {
+		t.Errorf("the game after the resumed one started at %s, expected %s", got, want)
+	}
+}
+
+func TestResumePosition(t *testing.T) {
+	runner := NewRunner()
+	start, err := board.ParseValidPosition("1X1O5/9/9/9/9/9/9/9/9 0 x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.SetStart(start); err != nil {
+		t.Fatal(err)
+	}
+	runner.newGame()
+	playFirstMoves(t, runner, 3)
+	rec := saveAndRead(t, runner)
+
+	resumed := NewRunner()
+	if err := resumed.Resume(rec); err != nil {
+		t.Fatal(err)
+	}
+	resumed.newGame()
+	if got, want := resumed.game.Position().Notation(), runner.game.Position().Notation(); got != want {
+		t.Errorf("resumed at %s, expected %s", got, want)
+	}
+	if got := resumed.StartPosition().Notation(); got != start.Notation() {
+		t.Errorf("resumed from %s, expected %s", got, start.Notation())
+	}
+}
+
+func TestResumeOver(t *testing.T) {
+	runner := NewRunner()
+	runner.newGame()
+	playFirstMoves(t, runner, -1)
+	if err := NewRunner().Resume(saveAndRead(t, runner)); err == nil {
+		t.Error("resumed a game that's over")
+	}
+}
+
+// unfinished games can't be saved to the database, which would count
+// them again once they're finished
+func TestSaveToDatabase(t *testing.T) {
+	runner := NewRunner()
+	runner.RecordPath = filepath.Join(t.TempDir(), "games.uttt")
+	runner.newGame()
+	player := NewTerminalPlayer(runner)
+	if err := runner.save(runner.RecordPath, runner.record(player, player, Outcome{Result: board.Result_ONGOING})); err == nil {
+		t.Error("saved an unfinished game to the database")
+	}
+}
+
+// firstMovePlayer always makes the first legal move, taking delay to
+// make each one
+type firstMovePlayer struct {
+	p     *board.Position
+	delay time.Duration
+}
+
+func (f *firstMovePlayer) name() string { return "first" }
+func (f *firstMovePlayer) getMove() (*board.Move, error) {
+	time.Sleep(f.delay)
+	return f.p.Moves()[0], nil
+}
+func (f *firstMovePlayer) displayBoard(p *board.Position, _ *board.Owner)      { f.p = p }
+func (f *firstMovePlayer) afterMove(*board.Position, *board.MoveResult, error) {}
+
+// every move is recorded with when it was made and how long it took
+func TestMoveTimes(t *testing.T) {
+	runner := NewRunner()
+	runner.RecordPath = ""
+	player := &firstMovePlayer{delay: 2 * time.Millisecond}
+	before := time.Now()
+	outcome := runner.run(player, player)
+	rec := runner.record(player, player, outcome)
+
+	stream := rec.ToProto()
+	if len(rec.Times) != len(rec.Moves) || len(stream.GetMoves()) != len(rec.Moves) {
+		t.Fatalf("%d moves have %d times, and %d in the record message", len(rec.Moves), len(rec.Times), len(stream.GetMoves()))
+	}
+	prev := before
+	for i, mt := range rec.Times {
+		if mt.At.Before(prev) || mt.Think < player.delay {
+			t.Errorf("move %d was made at %v after %v, expected after %v taking at least %v", i+1, mt.At, mt.Think, prev, player.delay)
+		}
+		prev = mt.At
+		if m := stream.GetMoves()[i]; m.GetTime() != mt.At.UnixMilli() || m.GetThinkTime() != mt.Think.Milliseconds() {
+			t.Errorf("move %d has time %d and think time %d in the record message, expected %d and %d",
+				i+1, m.GetTime(), m.GetThinkTime(), mt.At.UnixMilli(), mt.Think.Milliseconds())
+		}
+	}
+}
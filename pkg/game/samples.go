@@ -0,0 +1,140 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"uttt/pkg/board"
+	"uttt/pkg/npy"
+)
+
+// ========== Training Samples ==========
+// Every move played can be kept as a training sample: the observation
+// of the position it was played in (see board.Position.Observation),
+// which moves were legal, the move's index among the spaces (see
+// board.Size.MoveIndex) and how the game ended for the player who made
+// it. Samples are written as .npz shards holding the arrays
+// observations, masks, actions and outcomes, once there are enough of
+// them and once the runner is done playing.
+
+// the number of samples in each shard when none is given
+const DEFAULT_SHARD_SIZE = 4096
+
+// sampleWriter collects the samples of the games played and writes them
+// to numbered shards in a directory
+type sampleWriter struct {
+	dir       string
+	shardSize int
+	// the number of the next shard
+	shard int
+	// the size of the positions the samples are of
+	size board.Size
+
+	// the samples of finished games
+	observations []float32
+	masks        []bool
+	actions      []int64
+	outcomes     []float32
+
+	// the samples of the game being played, and who made each move
+	pending pendingSamples
+	players []board.Owner
+}
+
+type pendingSamples struct {
+	observations []float32
+	masks        []bool
+	actions      []int64
+}
+
+// starts writing samples of positions of the given size to the
+// directory, after any shards already in it
+func newSampleWriter(dir string, shardSize int, size board.Size) (*sampleWriter, error) {
+	if shardSize <= 0 {
+		shardSize = DEFAULT_SHARD_SIZE
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	sw := &sampleWriter{dir: dir, shardSize: shardSize, size: size}
+	for {
+		if _, err := os.Stat(sw.shardPath()); os.IsNotExist(err) {
+			break
+		}
+		sw.shard++
+	}
+	return sw, nil
+}
+
+// the path of the next shard
+func (sw *sampleWriter) shardPath() string {
+	return filepath.Join(sw.dir, fmt.Sprintf("shard-%05d.npz", sw.shard))
+}
+
+// keeps a sample of the move about to be made in the position
+func (sw *sampleWriter) add(p *board.Position, m *board.Move) {
+	idx, _ := p.Size().MoveIndex(m)
+	sw.pending.observations = append(sw.pending.observations, p.Observation()...)
+	sw.pending.masks = append(sw.pending.masks, p.LegalMask()...)
+	sw.pending.actions = append(sw.pending.actions, int64(idx))
+	sw.players = append(sw.players, p.Turn())
+}
+
+// gives the samples of the game that just ended their outcomes: 1 for
+// the winner's moves, -1 for the loser's and 0 if nobody won. Games
+// that weren't decided, like abandoned ones, are dropped instead. A
+// shard is written if there are enough samples
+func (sw *sampleWriter) finish(outcome Outcome) error {
+	if outcome.Result == board.Result_ONGOING || outcome.Result == board.Result_ABANDONED {
+		sw.pending, sw.players = pendingSamples{}, nil
+		return nil
+	}
+	for _, player := range sw.players {
+		switch outcome.Winner {
+		case board.Owner_NONE:
+			sw.outcomes = append(sw.outcomes, 0)
+		case player:
+			sw.outcomes = append(sw.outcomes, 1)
+		default:
+			sw.outcomes = append(sw.outcomes, -1)
+		}
+	}
+	sw.observations = append(sw.observations, sw.pending.observations...)
+	sw.masks = append(sw.masks, sw.pending.masks...)
+	sw.actions = append(sw.actions, sw.pending.actions...)
+	sw.pending, sw.players = pendingSamples{}, nil
+
+	if len(sw.actions) >= sw.shardSize {
+		return sw.flush()
+	}
+	return nil
+}
+
+// writes the samples of finished games to the next shard, if there are any
+func (sw *sampleWriter) flush() error {
+	n := len(sw.actions)
+	if n == 0 {
+		return nil
+	}
+
+	f, err := os.Create(sw.shardPath())
+	if err != nil {
+		return err
+	}
+	err = npy.WriteNPZ(f,
+		npy.Array{Name: "observations", Shape: append([]int{n}, sw.size.ObservationShape()...), Data: sw.observations},
+		npy.Array{Name: "masks", Shape: []int{n, sw.size.Spaces()}, Data: sw.masks},
+		npy.Array{Name: "actions", Shape: []int{n}, Data: sw.actions},
+		npy.Array{Name: "outcomes", Shape: []int{n}, Data: sw.outcomes},
+	)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	sw.shard++
+	sw.observations, sw.masks, sw.actions, sw.outcomes = nil, nil, nil, nil
+	return nil
+}
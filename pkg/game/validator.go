@@ -1,39 +1,68 @@
 package game
 
-import "uttt/pkg/board"
+import (
+	"errors"
+	"uttt/pkg/board"
+)
 
-// whether or not a move is valid
-func validateMove(b *board.Board, m *board.Move) bool {
-	// if the cell isn't valid for the current move
-	if !validateMoveCell(b, m.Large) {
-		return false
-	}
+// the reasons a move can be rejected
+var (
+	ErrWrongCell  = errors.New("that isn't the current cell")
+	ErrSpaceTaken = errors.New("that space is already taken")
+	ErrCellClosed = errors.New("that cell is already won or full")
+	ErrOutOfRange = errors.New("that coordinate is out of range")
+	ErrGameOver   = errors.New("the game is already over")
+)
 
-	// if the destination space is taken
-	if b.Get(m.Large).(*board.Cell).Get(m.Small).Owner() != 0 {
-		return false
-	}
+// the Reason sent to clients for each rejection error
+var reasons = map[error]board.Reason{
+	ErrWrongCell:  board.Reason_WRONG_CELL,
+	ErrSpaceTaken: board.Reason_SPACE_TAKEN,
+	ErrCellClosed: board.Reason_CELL_CLOSED,
+	ErrOutOfRange: board.Reason_OUT_OF_RANGE,
+	ErrGameOver:   board.Reason_GAME_OVER,
+}
 
-	return true
+// converts an error returned by validateMove to a board.Reason
+func reasonOf(err error) board.Reason {
+	if err == nil {
+		return board.Reason_ACCEPTED
+	}
+	return reasons[err]
 }
 
-// whether or not a cell is fit for being the site of the current move
-func validateMoveCell(b *board.Board, c *board.Coord) bool {
-	// if it isn't valid for moves in general, it's not valid
-	if !validateCell(b, c) {
-		return false
+// validateMove returns nil if the player whose turn it is can make the
+// move in the position, otherwise it returns why the move isn't valid.
+// The runner only asks that player for a move, so it can't be out of turn
+func validateMove(p *board.Position, m *board.Move) error {
+	if result, _ := p.Result(); result != board.Result_ONGOING {
+		return ErrGameOver
+	}
+	size := p.Size()
+	path := m.Path()
+	if len(path) != size.Levels {
+		return ErrOutOfRange
+	}
+	for _, c := range path {
+		if !size.Contains(c) {
+			return ErrOutOfRange
+		}
 	}
 
-	// if the CurCell is valid, check if it isn't
-	if b.CurCell.Valid() {
-		return c.Row == b.CurCell.Row && c.Col == b.CurCell.Col
+	// if there's a current cell, the move has to be in it
+	for i, c := range p.Target() {
+		if path[i].Row != c.Row || path[i].Col != c.Col {
+			return ErrWrongCell
+		}
+	}
+	if !p.Open(path[:len(path)-1]...) {
+		return ErrCellClosed
 	}
 
-	return true
-}
+	// if the destination space is taken
+	if p.SpaceOwner(m) != board.Owner_NONE {
+		return ErrSpaceTaken
+	}
 
-// whether or not a cell is fit for moves in general
-func validateCell(b *board.Board, c *board.Coord) bool {
-	// the cell isn't owned and the cell isn't full
-	return b.Get(c).Owner() == board.Owner_NONE && !b.Get(c).(*board.Cell).Full()
+	return nil
 }
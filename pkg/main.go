@@ -1,17 +1,88 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"uttt/pkg/board"
 	"uttt/pkg/game"
+	"uttt/pkg/record"
 )
 
 func main() {
-	msg := "Please provide either `pvp` for Player vs Player, `pvai` for Player vs AI, `aivp` for AI vs Player, or `aivai` for AI vs AI"
+	msg := "Please provide either `pvp` for Player vs Player, `pvai` for Player vs AI, `aivp` for AI vs Player, `aivai` for AI vs AI, `perft <depth> [position]` to count positions, `show <position>` to print a position, `replay <file> [game]` to step through a recorded game, `db [filters]` to find recorded games, `render <position | file [game]>` to draw a position or game as an SVG, PNG or GIF image, or `convert <moves | file>` to translate moves between conventions"
 	if len(os.Args) > 1 {
 		runner := game.NewRunner()
 
 		mode := os.Args[1]
+		// the database, rendering and converting have their own flags
+		switch mode {
+		case "db":
+			queryDB(os.Args[2:])
+			return
+		case "render":
+			render(os.Args[2:])
+			return
+		case "convert":
+			convert(os.Args[2:])
+			return
+		}
+		flags := flag.NewFlagSet(mode, flag.ExitOnError)
+		rules := flags.String("rules", board.StandardRules{}.Name(), "the rules to play by; one of "+board.RuleNames())
+		opening := flags.String("opening", board.FreeOpening.String(), "where the first move can be made; free, center, or the index of a cell")
+		flags.IntVar(&runner.Size.Rows, "rows", board.DEFAULT_ROWS, fmt.Sprintf("the number of rows of cells, and of spaces in each cell; at most %d", board.MAX_SIDE))
+		flags.IntVar(&runner.Size.Cols, "cols", board.DEFAULT_COLS, fmt.Sprintf("the number of columns of cells, and of spaces in each cell; at most %d", board.MAX_SIDE))
+		flags.IntVar(&runner.Size.InARow, "inarow", 0, "how many in a row win a cell or the board; 0 means the shorter side")
+		flags.IntVar(&runner.Size.Levels, "levels", board.DEFAULT_LEVELS, fmt.Sprintf("how deep cells nest; 2 is the standard game, at most %d", board.MAX_LEVELS))
+		flags.DurationVar(&runner.MoveTimeout, "timeout", 0, "how long an AI gets to make each move, e.g. 5s; 0 means forever")
+		flags.IntVar(&runner.MaxInvalidMoves, "max-invalid", 0, "how many invalid moves in a row forfeit the game; 0 means unlimited")
+		flags.StringVar(&runner.RecordPath, "record", "games.uttt", "the game database every game's record is appended to; empty to not record games")
+		flags.StringVar(&runner.SamplePath, "samples", "", "a directory to write a training sample of every move to, as .npz shards; empty to not write samples")
+		flags.IntVar(&runner.ShardSize, "shard-size", game.DEFAULT_SHARD_SIZE, "how many samples each shard holds")
+		position := flags.String("position", "", "the position games start from, in the format printed by show; its size and rules replace the flags")
+		resume := flags.String("resume", "", "a file a quit game was saved to; its last game is continued, and its size and rules replace the flags")
+		flags.Parse(os.Args[2:])
+
+		runner.Size = runner.Size.WithDefaults()
+
+		var err error
+		if err = runner.Size.Validate(); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		if runner.Rules, err = board.RulesByName(*rules); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		if runner.Opening, err = board.ParseOpening(*opening, runner.Size); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		if *position != "" {
+			p, err := board.ParsePosition(*position)
+			if err == nil {
+				err = runner.SetStart(p)
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(2)
+			}
+			runner.Size, runner.Rules = p.Size(), p.Rules()
+		}
+		if *resume != "" {
+			records, err := record.ReadFile(*resume)
+			if err == nil && len(records) == 0 {
+				err = fmt.Errorf("no games in %s", *resume)
+			}
+			if err == nil {
+				err = runner.Resume(records[len(records)-1])
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(2)
+			}
+		}
+
 		switch mode {
 		case "pvp":
 			runner.RunPVP()
@@ -21,6 +92,12 @@ func main() {
 			runner.RunAIVP()
 		case "aivai":
 			runner.RunAIs()
+		case "perft":
+			perft(runner, flags.Args())
+		case "show":
+			show(flags.Args())
+		case "replay":
+			replay(runner, flags.Args())
 		default:
 			fmt.Println("That is not a valid option.")
 			fmt.Println(msg)
@@ -0,0 +1,109 @@
+package npy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ========== NumPy Arrays ==========
+// Arrays are written in NumPy's .npy format (version 1.0), which
+// np.load reads straight into an array, and bundled into .npz files,
+// which are zip files of .npy files.
+
+// the start of every .npy file
+const MAGIC = "\x93NUMPY"
+
+// headers are padded so that the data starts on a multiple of this
+const HEADER_ALIGN = 64
+
+// Array is an array to write. Data holds its values flattened in
+// row-major order, as one of []float32, []float64, []int64, []int32,
+// []int8, []uint8 or []bool
+type Array struct {
+	// the name of the array in a .npz file, without .npy
+	Name  string
+	Shape []int
+	Data  interface{}
+}
+
+// the NumPy type of the array's values and the number of them
+func (a Array) descr() (descr string, n int, err error) {
+	switch data := a.Data.(type) {
+	case []float32:
+		return "<f4", len(data), nil
+	case []float64:
+		return "<f8", len(data), nil
+	case []int64:
+		return "<i8", len(data), nil
+	case []int32:
+		return "<i4", len(data), nil
+	case []int8:
+		return "|i1", len(data), nil
+	case []uint8:
+		return "|u1", len(data), nil
+	case []bool:
+		return "|b1", len(data), nil
+	}
+	return "", 0, fmt.Errorf("can't write %T as an array", a.Data)
+}
+
+// Write writes the array to w as a .npy file
+func Write(w io.Writer, a Array) error {
+	descr, n, err := a.descr()
+	if err != nil {
+		return err
+	}
+	size := 1
+	dims := make([]string, len(a.Shape))
+	for i, d := range a.Shape {
+		size *= d
+		dims[i] = strconv.Itoa(d)
+	}
+	if size != n {
+		return fmt.Errorf("array %s has %d values, but its shape %v needs %d", a.Name, n, a.Shape, size)
+	}
+	shape := strings.Join(dims, ", ")
+	if len(dims) == 1 {
+		shape += ","
+	}
+
+	// the header is a Python dict, padded with spaces and ending in a newline
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, shape)
+	prefix := len(MAGIC) + 4
+	pad := HEADER_ALIGN - (prefix+len(header)+1)%HEADER_ALIGN
+	if pad == HEADER_ALIGN {
+		pad = 0
+	}
+	header += strings.Repeat(" ", pad) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString(MAGIC)
+	buf.Write([]byte{1, 0})
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, a.Data)
+}
+
+// WriteNPZ writes the arrays to w as a compressed .npz file, which
+// np.load reads as a dict of the arrays by name
+func WriteNPZ(w io.Writer, arrays ...Array) error {
+	zw := zip.NewWriter(w)
+	for _, a := range arrays {
+		f, err := zw.Create(a.Name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := Write(f, a); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,121 @@
+package npy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// splits a .npy file into its header and data, checking the parts
+// that don't depend on the array
+func readNPY(t *testing.T, data []byte) (header string, values []byte) {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte(MAGIC)) {
+		t.Fatalf("starts with %q, expected %q", data[:6], MAGIC)
+	}
+	if major, minor := data[6], data[7]; major != 1 || minor != 0 {
+		t.Fatalf("version %d.%d, expected 1.0", major, minor)
+	}
+	n := int(binary.LittleEndian.Uint16(data[8:10]))
+	start := 10 + n
+	if start%HEADER_ALIGN != 0 || start > len(data) {
+		t.Fatalf("data starts at %d, expected a multiple of %d", start, HEADER_ALIGN)
+	}
+	header = string(data[10:start])
+	if !strings.HasSuffix(header, "\n") {
+		t.Fatalf("header %q doesn't end in a newline", header)
+	}
+	return strings.TrimRight(header, " \n"), data[start:]
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		array  Array
+		header string
+		values []byte
+	}{
+		{Array{Shape: []int{2, 3}, Data: []float32{1, 2, 3, 4, 5, 6}},
+			"{'descr': '<f4', 'fortran_order': False, 'shape': (2, 3), }", nil},
+		{Array{Shape: []int{3}, Data: []int64{1, -1, 256}},
+			"{'descr': '<i8', 'fortran_order': False, 'shape': (3,), }",
+			[]byte{1, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 1, 0, 0, 0, 0, 0, 0}},
+		{Array{Shape: []int{2, 2}, Data: []bool{true, false, false, true}},
+			"{'descr': '|b1', 'fortran_order': False, 'shape': (2, 2), }", []byte{1, 0, 0, 1}},
+		{Array{Shape: []int{1, 1, 2}, Data: []uint8{7, 8}},
+			"{'descr': '|u1', 'fortran_order': False, 'shape': (1, 1, 2), }", []byte{7, 8}},
+		{Array{Shape: []int{0}, Data: []float64{}},
+			"{'descr': '<f8', 'fortran_order': False, 'shape': (0,), }", []byte{}},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, test.array); err != nil {
+			t.Fatal(err)
+		}
+		header, values := readNPY(t, buf.Bytes())
+		if header != test.header {
+			t.Errorf("header %q, expected %q", header, test.header)
+		}
+		want := test.values
+		if want == nil {
+			var data bytes.Buffer
+			binary.Write(&data, binary.LittleEndian, test.array.Data)
+			want = data.Bytes()
+		}
+		if !bytes.Equal(values, want) {
+			t.Errorf("%s: data %v, expected %v", test.header, values, want)
+		}
+	}
+}
+
+func TestWriteInvalid(t *testing.T) {
+	for _, a := range []Array{
+		{Name: "shape", Shape: []int{2, 2}, Data: []float32{1, 2, 3}},
+		{Name: "type", Shape: []int{1}, Data: []string{"a"}},
+	} {
+		if err := Write(io.Discard, a); err == nil {
+			t.Errorf("%s: wrote an invalid array", a.Name)
+		}
+	}
+}
+
+func TestWriteNPZ(t *testing.T) {
+	arrays := []Array{
+		{Name: "observations", Shape: []int{2, 2}, Data: []float32{1, 0, 0, 1}},
+		{Name: "actions", Shape: []int{2}, Data: []int64{4, 40}},
+	}
+	var buf bytes.Buffer
+	if err := WriteNPZ(&buf, arrays...); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for i, f := range zr.File {
+		names = append(names, f.Name)
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want bytes.Buffer
+		if err := Write(&want, arrays[i]); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, want.Bytes()) {
+			t.Errorf("%s holds %v, expected %v", f.Name, data, want.Bytes())
+		}
+	}
+	if !reflect.DeepEqual(names, []string{"observations.npy", "actions.npy"}) {
+		t.Errorf("the .npz holds %v", names)
+	}
+}
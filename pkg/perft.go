@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"uttt/pkg/board"
+	"uttt/pkg/game"
+)
+
+// runs `uttt perft [flags] <depth> [position]`, printing the perft count
+// below every legal move and then the total. The position is in the
+// format printed by show and has to pass board.Validate; without one,
+// perft starts from the runner's start position
+func perft(runner *game.Runner, args []string) {
+	if len(args) < 1 {
+		fmt.Println("usage: uttt perft [flags] <depth> [position]")
+		os.Exit(2)
+	}
+	depth, err := strconv.Atoi(args[0])
+	if err != nil || depth < 0 {
+		fmt.Printf("invalid depth %q\n", args[0])
+		os.Exit(2)
+	}
+
+	p := runner.StartPosition()
+	if len(args) > 1 {
+		// the position's fields don't have to be quoted together
+		if p, err = board.ParseValidPosition(strings.Join(args[1:], " ")); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	start := time.Now()
+	var nodes uint64
+	if depth == 0 {
+		nodes = 1
+	}
+	size := p.Size()
+	for _, mc := range board.Divide(p, depth) {
+		fmt.Printf("%s: %d\n", size.MoveString(mc.Move), mc.Count)
+		nodes += mc.Count
+	}
+	fmt.Printf("\nnodes: %d (%v)\n", nodes, time.Since(start).Round(time.Millisecond))
+}
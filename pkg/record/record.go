@@ -0,0 +1,475 @@
+package record
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"uttt/pkg/board"
+)
+
+// ========== Game Records ==========
+// A game record is a game written as text, like PGN in chess:
+//
+//	[Player1 "human"]
+//	[Player2 "ai"]
+//	[Date "2026.01.02"]
+//	[Rules "standard"]
+//	[Size "3x3"]
+//	[Opening "free"]
+//	[TimeControl "-"]
+//	[Result "1-0"]
+//	[Termination "normal"]
+//
+//	1. 4.0 {[%time 2026-01-02T03:04:05.120Z] [%emt 1.5s]} 0.4 2. 4.4
+//	{a comment} 4.8 1-0
+//
+// Headers come first, one per line. The moves follow, numbered by
+// PLAYER1's moves and written as the indices of their coordinates
+// split by . (see board.Size.MoveString), with comments in braces.
+// Like clock commands in PGN, [%time] and [%emt] in a comment are
+// when the move before it was made and how long it took, if known.
+// The result ends the game; a file can hold any number of games,
+// split by blank lines.
+
+// the layout of the Date header
+const DATE_FORMAT = "2006.01.02"
+
+// the layout of [%time] commands, in UTC to the millisecond
+const TIME_FORMAT = "2006-01-02T15:04:05.000Z07:00"
+
+// MoveTime is when a move was made and how long it took; zero if unknown
+type MoveTime struct {
+	At    time.Time
+	Think time.Duration
+}
+
+func (mt MoveTime) known() bool {
+	return !mt.At.IsZero() || mt.Think != 0
+}
+
+// Record is a game along with what's known about how it was played
+type Record struct {
+	// who played each side
+	Player1, Player2 string
+	Date             time.Time
+	Rules            board.Rules
+	Size             board.Size
+	Opening          board.Opening
+	// the notation of the position the game started from if it
+	// wasn't an empty board following Opening
+	Position string
+	// how long each move could take; 0 if there was no limit
+	TimeControl time.Duration
+	Result      board.Result
+	Winner      board.Owner
+
+	Moves []*board.Move
+	// when each move was made and how long it took, by move; it can be
+	// shorter than Moves, or empty, if the times aren't known
+	Times []MoveTime
+	// comments by the number of moves made before them
+	Comments map[int]string
+}
+
+// Start returns the position the game started from, returning an
+// error if it couldn't have come up in a game
+func (rec *Record) Start() (*board.Position, error) {
+	if rec.Position == "" {
+		return rec.Opening.NewPosition(rec.Size, rec.Rules), nil
+	}
+	p, err := board.ParseValidPosition(rec.Position)
+	if err != nil {
+		return nil, fmt.Errorf("position %q: %w", rec.Position, err)
+	}
+	return p, nil
+}
+
+// Game replays the record's moves from its start, returning an error
+// for the first one that isn't legal
+func (rec *Record) Game() (*board.Game, error) {
+	p, err := rec.Start()
+	if err != nil {
+		return nil, err
+	}
+	g := board.NewGame(p)
+	for i, m := range rec.Moves {
+		if !g.Position().Legal(m) {
+			return nil, fmt.Errorf("move %d (%s) isn't legal", i+1, rec.Size.MoveString(m))
+		}
+		g.Play(m)
+	}
+	return g, nil
+}
+
+// ========== Writing ==========
+
+// the result token ending a game and the Termination header of how it ended
+func resultTokens(result board.Result, winner board.Owner) (token, termination string) {
+	switch winner {
+	case board.Owner_PLAYER1:
+		token = "1-0"
+	case board.Owner_PLAYER2:
+		token = "0-1"
+	default:
+		token = "*"
+	}
+	if result == board.Result_DRAW {
+		token = "1/2-1/2"
+	}
+
+	switch result {
+	case board.Result_PLAYER1_WIN, board.Result_PLAYER2_WIN, board.Result_DRAW:
+		termination = "normal"
+	case board.Result_ONGOING:
+		termination = "unterminated"
+	default:
+		termination = strings.ToLower(result.String())
+	}
+	return
+}
+
+// quotes a header value, escaping quotes and backslashes
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Write writes the record in the format read by Parse, followed by a
+// blank line. It returns an error if a comment holds a }, which would
+// end it early
+func (rec *Record) Write(w io.Writer) error {
+	for i, comment := range rec.Comments {
+		if strings.Contains(comment, "}") {
+			return fmt.Errorf("the comment after %d moves holds a }: %q", i, comment)
+		}
+	}
+	token, termination := resultTokens(rec.Result, rec.Winner)
+	timeControl := "-"
+	if rec.TimeControl > 0 {
+		timeControl = rec.TimeControl.String()
+	}
+
+	var sb strings.Builder
+	header := func(key, value string) {
+		sb.WriteString("[" + key + " " + quote(value) + "]\n")
+	}
+	header("Player1", rec.Player1)
+	header("Player2", rec.Player2)
+	header("Date", rec.Date.Format(DATE_FORMAT))
+	header("Rules", rec.Rules.Name())
+	header("Size", rec.Size.Tag())
+	header("Opening", rec.Opening.String())
+	if rec.Position != "" {
+		header("Position", rec.Position)
+	}
+	header("TimeControl", timeControl)
+	header("Result", token)
+	header("Termination", termination)
+	sb.WriteString("\n")
+
+	// moves are numbered by PLAYER1's moves, so a game starting
+	// with PLAYER2 to move starts with "1..."
+	number, line := 1, 0
+	first := board.Owner_PLAYER1
+	if start, err := rec.Start(); err == nil {
+		first = start.Turn()
+	}
+	write := func(token string) {
+		if line > 0 && line+len(token) >= 80 {
+			sb.WriteString("\n")
+			line = 0
+		} else if line > 0 {
+			sb.WriteString(" ")
+			line++
+		}
+		sb.WriteString(token)
+		line += len(token)
+	}
+	for i, m := range rec.Moves {
+		if comment, ok := rec.Comments[i]; ok {
+			write(commentToken(comment))
+		}
+		player1 := (i%2 == 0) == (first == board.Owner_PLAYER1)
+		switch {
+		case player1:
+			write(strconv.Itoa(number) + ".")
+		case i == 0:
+			write(strconv.Itoa(number) + "...")
+		}
+		if !player1 {
+			number++
+		}
+		write(rec.Size.MoveString(m))
+		if i < len(rec.Times) && rec.Times[i].known() {
+			write(timeToken(rec.Times[i]))
+		}
+	}
+	if comment, ok := rec.Comments[len(rec.Moves)]; ok {
+		write(commentToken(comment))
+	}
+	write(token)
+	sb.WriteString("\n\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// the comment in braces. Its whitespace is collapsed the way Parse
+// collapses it, so that it can't start a line that looks like a header
+func commentToken(comment string) string {
+	return "{" + strings.Join(strings.Fields(comment), " ") + "}"
+}
+
+// the comment holding when a move was made and how long it took
+func timeToken(mt MoveTime) string {
+	var commands []string
+	if !mt.At.IsZero() {
+		commands = append(commands, "[%time "+mt.At.UTC().Format(TIME_FORMAT)+"]")
+	}
+	if mt.Think != 0 {
+		commands = append(commands, "[%emt "+mt.Think.Round(time.Millisecond).String()+"]")
+	}
+	return "{" + strings.Join(commands, " ") + "}"
+}
+
+// ========== Parsing ==========
+
+// Parse reads every game record in r
+func Parse(r io.Reader) ([]*Record, error) {
+	var records []*Record
+	var rec *Record
+	var headers map[string]string
+	var moves []string
+	lineNum := 0
+
+	// converts the headers and moves read so far into rec
+	finish := func() error {
+		if headers == nil {
+			return nil
+		}
+		var err error
+		if rec, err = fromHeaders(headers); err != nil {
+			return fmt.Errorf("game %d: %w", len(records)+1, err)
+		}
+		if err := rec.parseMoves(strings.Join(moves, "\n")); err != nil {
+			return fmt.Errorf("game %d: %w", len(records)+1, err)
+		}
+		records = append(records, rec)
+		headers, moves = nil, nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "["):
+			// headers after moves start the next game
+			if len(moves) > 0 {
+				if err := finish(); err != nil {
+					return nil, err
+				}
+			}
+			key, value, err := parseHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			if headers == nil {
+				headers = map[string]string{}
+			}
+			headers[key] = value
+		default:
+			if headers == nil {
+				return nil, fmt.Errorf("line %d: expected headers before moves", lineNum)
+			}
+			moves = append(moves, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ReadFile reads every game record in the file at path
+func ReadFile(path string) ([]*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// reads a header line such as [Rules "standard"]
+func parseHeader(line string) (key, value string, err error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("invalid header %s", line)
+	}
+	key, quoted, found := strings.Cut(line[1:len(line)-1], " ")
+	if !found {
+		return "", "", fmt.Errorf("invalid header %s", line)
+	}
+	if value, err = strconv.Unquote(strings.TrimSpace(quoted)); err != nil {
+		return "", "", fmt.Errorf("invalid header %s", line)
+	}
+	return key, value, nil
+}
+
+// makes a record without moves from its headers
+func fromHeaders(headers map[string]string) (*Record, error) {
+	rec := &Record{Player1: headers["Player1"], Player2: headers["Player2"], Position: headers["Position"], Comments: map[int]string{}}
+	var err error
+	if date, ok := headers["Date"]; ok {
+		if rec.Date, err = time.Parse(DATE_FORMAT, date); err != nil {
+			return nil, fmt.Errorf("invalid date %q", date)
+		}
+	}
+
+	rec.Rules = board.StandardRules{}
+	if rules, ok := headers["Rules"]; ok {
+		if rec.Rules, err = board.RulesByName(rules); err != nil {
+			return nil, err
+		}
+	}
+	rec.Size = board.DefaultSize()
+	if size, ok := headers["Size"]; ok {
+		if rec.Size, err = board.ParseSize(size); err != nil {
+			return nil, err
+		}
+	}
+	rec.Opening = board.FreeOpening
+	if opening, ok := headers["Opening"]; ok {
+		if rec.Opening, err = board.ParseOpening(opening, rec.Size); err != nil {
+			return nil, err
+		}
+	}
+	if tc, ok := headers["TimeControl"]; ok && tc != "-" {
+		if rec.TimeControl, err = time.ParseDuration(tc); err != nil {
+			return nil, fmt.Errorf("invalid time control %q", tc)
+		}
+	}
+	if rec.Result, rec.Winner, err = parseResult(headers["Result"], headers["Termination"]); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// converts the Result and Termination headers back to how the game ended
+func parseResult(token, termination string) (board.Result, board.Owner, error) {
+	winner := board.Owner_NONE
+	switch token {
+	case "1-0":
+		winner = board.Owner_PLAYER1
+	case "0-1":
+		winner = board.Owner_PLAYER2
+	case "1/2-1/2":
+		return board.Result_DRAW, winner, nil
+	case "*", "":
+	default:
+		return board.Result_ONGOING, winner, fmt.Errorf("invalid result %q, expected 1-0, 0-1, 1/2-1/2 or *", token)
+	}
+
+	switch termination {
+	case "normal", "":
+		switch winner {
+		case board.Owner_PLAYER1:
+			return board.Result_PLAYER1_WIN, winner, nil
+		case board.Owner_PLAYER2:
+			return board.Result_PLAYER2_WIN, winner, nil
+		}
+		return board.Result_ONGOING, winner, nil
+	case "unterminated":
+		return board.Result_ONGOING, winner, nil
+	}
+	result, ok := board.Result_value[strings.ToUpper(termination)]
+	if !ok {
+		return board.Result_ONGOING, winner, fmt.Errorf("invalid termination %q", termination)
+	}
+	return board.Result(result), winner, nil
+}
+
+// reads the move text of a game, which ends with its result
+func (rec *Record) parseMoves(text string) error {
+	for text = strings.TrimSpace(text); text != ""; text = strings.TrimSpace(text) {
+		if text[0] == '{' {
+			end := strings.IndexByte(text, '}')
+			if end < 0 {
+				return fmt.Errorf("unterminated comment %s", text)
+			}
+			comment, err := rec.parseCommands(text[1:end])
+			if err != nil {
+				return err
+			}
+			text = text[end+1:]
+			if comment == "" {
+				continue
+			}
+			if prev, ok := rec.Comments[len(rec.Moves)]; ok {
+				comment = prev + " " + comment
+			}
+			rec.Comments[len(rec.Moves)] = comment
+			continue
+		}
+
+		token := text
+		if end := strings.IndexAny(text, " \n{"); end >= 0 {
+			token = text[:end]
+		}
+		text = text[len(token):]
+		switch {
+		case token == "1-0" || token == "0-1" || token == "1/2-1/2" || token == "*":
+			// the result is also in the headers
+		case strings.HasSuffix(token, "."):
+			// move numbers only help people reading the record
+		default:
+			m, err := rec.Size.ParseMove(token)
+			if err != nil {
+				return err
+			}
+			rec.Moves = append(rec.Moves, m)
+		}
+	}
+	return nil
+}
+
+// reads the [%time] and [%emt] commands in a comment into the time of
+// the move before it, and returns the rest of the comment
+func (rec *Record) parseCommands(comment string) (string, error) {
+	var rest []string
+	for _, field := range strings.Fields(comment) {
+		rest = append(rest, field)
+		n := len(rest)
+		if n < 2 || !strings.HasSuffix(field, "]") || (rest[n-2] != "[%time" && rest[n-2] != "[%emt") {
+			continue
+		}
+		if len(rec.Moves) == 0 {
+			return "", fmt.Errorf("%s %s before the first move", rest[n-2], field)
+		}
+		for len(rec.Times) < len(rec.Moves) {
+			rec.Times = append(rec.Times, MoveTime{})
+		}
+		mt := &rec.Times[len(rec.Moves)-1]
+		value := strings.TrimSuffix(field, "]")
+		var err error
+		if rest[n-2] == "[%time" {
+			mt.At, err = time.Parse(TIME_FORMAT, value)
+		} else {
+			mt.Think, err = time.ParseDuration(value)
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %s", rest[n-2], field)
+		}
+		rest = rest[:n-2]
+	}
+	return strings.Join(rest, " "), nil
+}
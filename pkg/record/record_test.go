@@ -0,0 +1,116 @@
+package record
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"uttt/pkg/board"
+)
+
+func TestWriteParse(t *testing.T) {
+	recs := []*Record{
+		{Player1: "human", Player2: `a "quoted" \\ name`, Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			Rules: board.StandardRules{}, Size: board.DefaultSize(), Opening: board.FreeOpening, TimeControl: 5 * time.Second,
+			Result: board.Result_RESIGNATION, Winner: board.Owner_PLAYER2,
+			Comments: map[int]string{0: "before the first move", 3: "after three moves", 12: "at the end"}},
+		{Player1: "ai", Player2: "ai", Date: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			Rules: board.TiebreakRules{}, Size: board.Size{Rows: 4, Cols: 4, InARow: 3, Levels: 2}, Opening: board.CenterOpening,
+			Result: board.Result_DRAW, Winner: board.Owner_NONE, Comments: map[int]string{}},
+		// PLAYER2 moves first
+		{Player1: "a", Player2: "b", Date: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+			Rules: board.StandardRules{}, Size: board.DefaultSize(), Opening: board.FreeOpening, Position: "X8/9/9/9/9/9/9/9/9 0 o",
+			Result: board.Result_ONGOING, Winner: board.Owner_NONE, Comments: map[int]string{1: "a comment\nover [two] lines"}},
+	}
+	for i, n := range []int{12, 20, 5} {
+		playMoves(t, recs[i], n)
+	}
+	// the third move has a time and a comment after it, and later moves
+	// only part of their times
+	at := time.Date(2026, 1, 2, 3, 4, 5, 120e6, time.UTC)
+	recs[0].Times = []MoveTime{2: {At: at, Think: 1500 * time.Millisecond}, 4: {At: at.Add(time.Minute)}, 5: {Think: 20 * time.Millisecond}}
+
+	var buf bytes.Buffer
+	for _, rec := range recs {
+		if err := rec.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	text := buf.String()
+	for _, want := range []string{`[Player2 "a \"quoted\" \\\\ name"]`, `[Size "4x4k3"]`, `[Opening "center"]`, `[TimeControl "5s"]`, `[Result "0-1"]`, `[Termination "resignation"]`, "{[%time 2026-01-02T03:04:05.120Z] [%emt 1.5s]} {after three moves}", "{[%emt 20ms]}", "\n1... "} {
+		if !strings.Contains(text, want) {
+			t.Errorf("written records don't hold %q:\n%s", want, text)
+		}
+	}
+
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(recs) {
+		t.Fatalf("parsed %d games, expected %d", len(parsed), len(recs))
+	}
+	recs[2].Comments[1] = "a comment over [two] lines"
+	for i, rec := range parsed {
+		want := recs[i]
+		if rec.Player1 != want.Player1 || rec.Player2 != want.Player2 || !rec.Date.Equal(want.Date) ||
+			rec.Rules.Name() != want.Rules.Name() || rec.Size != want.Size || rec.Opening != want.Opening ||
+			rec.Position != want.Position || rec.TimeControl != want.TimeControl {
+			t.Errorf("game %d: parsed as %+v, expected %+v", i+1, rec, want)
+		}
+		if rec.Result != want.Result || rec.Winner != want.Winner {
+			t.Errorf("game %d: parsed with result %v %v, expected %v %v", i+1, rec.Result, rec.Winner, want.Result, want.Winner)
+		}
+		if !reflect.DeepEqual(moveStrings(rec), moveStrings(want)) || !reflect.DeepEqual(rec.Comments, want.Comments) {
+			t.Errorf("game %d: parsed with moves %v %v, expected %v %v", i+1, moveStrings(rec), rec.Comments, moveStrings(want), want.Comments)
+		}
+		if !timesEqual(rec.Times, want.Times) {
+			t.Errorf("game %d: parsed with times %v, expected %v", i+1, rec.Times, want.Times)
+		}
+		if _, err := rec.Game(); err != nil {
+			t.Errorf("game %d: %v", i+1, err)
+		}
+	}
+}
+
+// whether the move times are the same, ignoring trailing unknown ones
+func timesEqual(a, b []MoveTime) bool {
+	for len(a) > 0 && !a[len(a)-1].known() {
+		a = a[:len(a)-1]
+	}
+	for len(b) > 0 && !b[len(b)-1].known() {
+		b = b[:len(b)-1]
+	}
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].At.Equal(b[i].At) || a[i].Think != b[i].Think {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseTimes(t *testing.T) {
+	for _, moves := range []string{
+		"{[%emt 1s]} 1. 4.4 *",
+		"1. 4.4 {[%time yesterday]} *",
+		"1. 4.4 {[%emt fast]} *",
+	} {
+		text := "[Result \"*\"]\n\n" + moves + "\n"
+		if _, err := Parse(strings.NewReader(text)); err == nil {
+			t.Errorf("parsed %q without an error", moves)
+		}
+	}
+}
+
+// comments can't hold the } that would end them early
+func TestWriteBrace(t *testing.T) {
+	rec := &Record{Rules: board.StandardRules{}, Size: board.DefaultSize(), Opening: board.FreeOpening, Comments: map[int]string{0: "a } b"}}
+	var buf bytes.Buffer
+	if err := rec.Write(&buf); err == nil {
+		t.Errorf("wrote a comment holding a }:\n%s", buf.String())
+	}
+}
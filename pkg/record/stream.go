@@ -0,0 +1,170 @@
+package record
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"uttt/pkg/board"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// ========== Record Messages ==========
+// A record can also be a GameRecord message (see proto/board.proto),
+// which also has room for engine evaluations, which text records don't
+// keep. Files of them are length-delimited streams, where every message
+// is preceded by its size as a varint, the same as protodelim and Java's
+// writeDelimitedTo.
+
+// ToProto converts the record to a GameRecord message. The moves don't
+// have evaluations, which records don't keep
+func (rec *Record) ToProto() *board.GameRecord {
+	g := &board.GameRecord{
+		Player1:  &board.PlayerInfo{Name: rec.Player1},
+		Player2:  &board.PlayerInfo{Name: rec.Player2},
+		Rules:    rec.Rules.Name(),
+		Rows:     int32(rec.Size.Rows),
+		Cols:     int32(rec.Size.Cols),
+		Inarow:   int32(rec.Size.InARow),
+		Levels:   int32(rec.Size.Levels),
+		Opening:  rec.Opening.String(),
+		Position: rec.Position,
+		Result:   rec.Result,
+		Winner:   rec.Winner,
+		Comment:  rec.Comments[0],
+	}
+	if !rec.Date.IsZero() {
+		g.Started = rec.Date.UnixMilli()
+	}
+	g.TimeControl = rec.TimeControl.Milliseconds()
+
+	player := board.Owner_PLAYER1
+	if start, err := rec.Start(); err == nil {
+		player = start.Turn()
+	}
+	for i, m := range rec.Moves {
+		mr := &board.MoveRecord{Move: m, Player: player, Comment: rec.Comments[i+1]}
+		if i < len(rec.Times) {
+			if !rec.Times[i].At.IsZero() {
+				mr.Time = rec.Times[i].At.UnixMilli()
+			}
+			mr.ThinkTime = rec.Times[i].Think.Milliseconds()
+		}
+		g.Moves = append(g.Moves, mr)
+		player = player.Opponent()
+	}
+	return g
+}
+
+// FromProto converts a GameRecord message to a record, leaving out the
+// moves' evaluations. It returns an error if the message's rules, size
+// or opening are invalid; the moves aren't checked
+func FromProto(g *board.GameRecord) (*Record, error) {
+	rec := &Record{
+		Player1:  g.GetPlayer1().GetName(),
+		Player2:  g.GetPlayer2().GetName(),
+		Size:     g.Size(),
+		Position: g.GetPosition(),
+		Result:   g.GetResult(),
+		Winner:   g.GetWinner(),
+		Comments: map[int]string{},
+	}
+	if err := rec.Size.Validate(); err != nil {
+		return nil, err
+	}
+	if g.GetStarted() != 0 {
+		rec.Date = time.UnixMilli(g.GetStarted())
+	}
+	rec.TimeControl = time.Duration(g.GetTimeControl()) * time.Millisecond
+
+	var err error
+	rec.Rules = board.StandardRules{}
+	if g.GetRules() != "" {
+		if rec.Rules, err = board.RulesByName(g.GetRules()); err != nil {
+			return nil, err
+		}
+	}
+	rec.Opening = board.FreeOpening
+	if g.GetOpening() != "" {
+		if rec.Opening, err = board.ParseOpening(g.GetOpening(), rec.Size); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.GetComment() != "" {
+		rec.Comments[0] = g.GetComment()
+	}
+	for i, m := range g.GetMoves() {
+		if m.GetMove() == nil {
+			return nil, fmt.Errorf("move %d is missing", i+1)
+		}
+		rec.Moves = append(rec.Moves, m.GetMove())
+		if m.GetComment() != "" {
+			rec.Comments[i+1] = m.GetComment()
+		}
+		if m.GetTime() != 0 || m.GetThinkTime() != 0 {
+			for len(rec.Times) <= i {
+				rec.Times = append(rec.Times, MoveTime{})
+			}
+			if m.GetTime() != 0 {
+				rec.Times[i].At = time.UnixMilli(m.GetTime())
+			}
+			rec.Times[i].Think = time.Duration(m.GetThinkTime()) * time.Millisecond
+		}
+	}
+	return rec, nil
+}
+
+// ========== Streams ==========
+
+// StreamWriter writes GameRecord messages to a length-delimited stream
+type StreamWriter struct {
+	w io.Writer
+}
+
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{w: w}
+}
+
+// Write writes the message, preceded by its size
+func (sw *StreamWriter) Write(g *board.GameRecord) error {
+	_, err := protodelim.MarshalTo(sw.w, g)
+	return err
+}
+
+// StreamReader reads GameRecord messages from a length-delimited stream
+type StreamReader struct {
+	r *bufio.Reader
+}
+
+func NewStreamReader(r io.Reader) *StreamReader {
+	return &StreamReader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next message, or io.EOF once there are none left.
+// A stream that ends in the middle of a message is io.ErrUnexpectedEOF
+func (sr *StreamReader) Read() (*board.GameRecord, error) {
+	g := &board.GameRecord{}
+	if err := protodelim.UnmarshalFrom(sr.r, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ReadStream reads every message in the stream
+func ReadStream(r io.Reader) ([]*board.GameRecord, error) {
+	sr := NewStreamReader(r)
+	var games []*board.GameRecord
+	for {
+		g, err := sr.Read()
+		if errors.Is(err, io.EOF) {
+			return games, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %w", len(games)+1, err)
+		}
+		games = append(games, g)
+	}
+}
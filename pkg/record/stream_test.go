@@ -0,0 +1,108 @@
+package record
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+	"uttt/pkg/board"
+)
+
+// plays n moves from the start of the record, always making the first legal move
+func playMoves(t *testing.T, rec *Record, n int) {
+	p, err := rec.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		m := p.Moves()[0]
+		rec.Moves = append(rec.Moves, m)
+		p.Apply(m)
+	}
+}
+
+func TestStream(t *testing.T) {
+	recs := []*Record{
+		{Player1: "human", Player2: "ai", Rules: board.StandardRules{}, Size: board.DefaultSize(), Opening: board.FreeOpening,
+			Date: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), TimeControl: 5 * time.Second,
+			Result: board.Result_RESIGNATION, Winner: board.Owner_PLAYER2, Comments: map[int]string{0: "start", 2: "after two"}},
+		{Player1: "a", Player2: "b", Rules: board.MisereRules{}, Size: board.Size{Rows: 4, Cols: 4, InARow: 3, Levels: 2}, Opening: board.CenterOpening,
+			Result: board.Result_ONGOING, Winner: board.Owner_NONE, Comments: map[int]string{}},
+		{Player1: "a", Player2: "b", Rules: board.StandardRules{}, Size: board.DefaultSize(), Opening: board.FreeOpening,
+			Position: "1X1O5/9/9/9/9/9/9/9/9 0 x", Result: board.Result_DRAW, Winner: board.Owner_NONE, Comments: map[int]string{}},
+	}
+	for i, rec := range recs {
+		playMoves(t, rec, 5+i)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 6, 250e6, time.UTC)
+	recs[0].Times = []MoveTime{{At: at, Think: time.Second}, {At: at.Add(2 * time.Second), Think: 1750 * time.Millisecond}, 3: {Think: 5 * time.Millisecond}}
+
+	var buf bytes.Buffer
+	sw := NewStreamWriter(&buf)
+	for _, rec := range recs {
+		if err := sw.Write(rec.ToProto()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	times := [][2]int64{{at.UnixMilli(), 1000}, {at.UnixMilli() + 2000, 1750}, {0, 0}, {0, 5}, {0, 0}}
+	for i, m := range recs[0].ToProto().GetMoves() {
+		if got := [2]int64{m.GetTime(), m.GetThinkTime()}; got != times[i] {
+			t.Errorf("move %d has time and think time %v, expected %v", i+1, got, times[i])
+		}
+	}
+
+	games, err := ReadStream(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != len(recs) {
+		t.Fatalf("read %d games, expected %d", len(games), len(recs))
+	}
+	for i, g := range games {
+		rec, want := mustFromProto(t, g), recs[i]
+		if rec.Player1 != want.Player1 || rec.Player2 != want.Player2 || rec.Rules.Name() != want.Rules.Name() ||
+			rec.Size != want.Size || rec.Opening != want.Opening || rec.Position != want.Position ||
+			!rec.Date.Equal(want.Date) || rec.TimeControl != want.TimeControl {
+			t.Errorf("game %d: read back as %+v, expected %+v", i+1, rec, want)
+		}
+		if rec.Result != want.Result || rec.Winner != want.Winner {
+			t.Errorf("game %d: read back with result %v %v, expected %v %v", i+1, rec.Result, rec.Winner, want.Result, want.Winner)
+		}
+		if !reflect.DeepEqual(moveStrings(rec), moveStrings(want)) || !reflect.DeepEqual(rec.Comments, want.Comments) {
+			t.Errorf("game %d: read back with moves %v %v, expected %v %v", i+1, moveStrings(rec), rec.Comments, moveStrings(want), want.Comments)
+		}
+		if !timesEqual(rec.Times, want.Times) {
+			t.Errorf("game %d: read back with times %v, expected %v", i+1, rec.Times, want.Times)
+		}
+	}
+}
+
+// a stream cut off in the middle of a message is an error
+func TestStreamTruncated(t *testing.T) {
+	rec := &Record{Rules: board.StandardRules{}, Size: board.DefaultSize(), Opening: board.FreeOpening}
+	playMoves(t, rec, 3)
+	var buf bytes.Buffer
+	if err := NewStreamWriter(&buf).Write(rec.ToProto()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadStream(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Error("read a truncated stream without an error")
+	}
+}
+
+func mustFromProto(t *testing.T, g *board.GameRecord) *Record {
+	rec, err := FromProto(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+// the record's moves as written in it
+func moveStrings(rec *Record) []string {
+	var moves []string
+	for _, m := range rec.Moves {
+		moves = append(moves, rec.Size.MoveString(m))
+	}
+	return moves
+}
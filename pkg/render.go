@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"uttt/pkg/board"
+	"uttt/pkg/record"
+)
+
+// runs `uttt render [flags] <position | file [game]>`, drawing a
+// position, or a recorded game (the last one in the file unless a game
+// number is given) after some of its moves. It's an SVG image unless
+// the output file ends in .png, or --gif animates the whole game. Flags
+// can go before or after the position or file
+func render(args []string) {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	out := flags.String("out", "", "the file to write the image to, as a PNG if it ends in .png and SVG otherwise; empty for stdout")
+	move := flags.Int("move", -1, "how many moves of a recorded game to draw the position after; -1 for all of them")
+	animate := flags.Bool("gif", false, "draw a recorded game up to --move as an animated GIF, with a frame per move")
+	delay := flags.Duration("delay", time.Second, "how long each frame of a GIF shows")
+	space := flags.Int("space", board.RENDER_SPACE_SIZE, "the side of a space in pixels")
+	heat := flags.String("heat", "", "a file of a value for every space by index, split by spaces, commas or newlines, to draw as heat")
+	args = parseInterspersed(flags, args)
+	if len(args) < 1 {
+		fmt.Println("usage: uttt render [flags] <position | file [game]>")
+		os.Exit(2)
+	}
+
+	g, err := renderedGame(args, *move)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	p := g.Position()
+	opts := board.RenderOptions{SpaceSize: *space}
+	if history := g.History(); len(history) > 0 {
+		opts.LastMove = history[len(history)-1]
+	}
+	if *heat != "" {
+		if opts.Heat, err = readHeat(*heat, p.Size().Spaces()); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	err = writeImage(w, g, opts, *out, *animate, *delay)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// parses the flags wherever they are among the args, since the flag
+// package stops at the first one that isn't a flag, and returns the
+// rest in order. Everything after "--" is kept as it is
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var rest []string
+	for len(args) > 0 {
+		flags.Parse(args)
+		parsed, left := args[:len(args)-flags.NArg()], flags.Args()
+		if len(parsed) > 0 && parsed[len(parsed)-1] == "--" {
+			return append(rest, left...)
+		}
+		if len(left) == 0 {
+			break
+		}
+		rest = append(rest, left[0])
+		args = left[1:]
+	}
+	return rest
+}
+
+// writes the game's position as an SVG image, or as a PNG if the
+// output file ends in .png, or the whole game as a GIF if animate is set
+func writeImage(w io.Writer, g *board.Game, opts board.RenderOptions, out string, animate bool, delay time.Duration) error {
+	switch {
+	case animate:
+		return gif.EncodeAll(w, g.GIF(opts, delay))
+	case strings.HasSuffix(out, ".png"):
+		return png.Encode(w, g.Position().Image(opts))
+	default:
+		_, err := io.WriteString(w, g.Position().SVG(opts))
+		return err
+	}
+}
+
+// the game to render, which is just a position if the args are one.
+// Otherwise they're a game record file and maybe a game number, and
+// the game is cut off after move moves unless it's -1
+func renderedGame(args []string, move int) (*board.Game, error) {
+	if _, err := os.Stat(args[0]); err != nil {
+		p, err := board.ParseValidPosition(strings.Join(args, " "))
+		if err != nil {
+			return nil, err
+		}
+		return board.NewGame(p), nil
+	}
+
+	records, err := record.ReadFile(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no games in %s", args[0])
+	}
+	num := len(records)
+	if len(args) > 1 {
+		if num, err = strconv.Atoi(args[1]); err != nil || num < 1 || num > len(records) {
+			return nil, fmt.Errorf("invalid game %q, expected 1 to %d", args[1], len(records))
+		}
+	}
+	rec := records[num-1]
+	if move < -1 || move > len(rec.Moves) {
+		return nil, fmt.Errorf("invalid move %d, expected 0 to %d", move, len(rec.Moves))
+	}
+	if move >= 0 {
+		rec.Moves = rec.Moves[:move]
+	}
+	g, err := rec.Game()
+	if err != nil {
+		return nil, fmt.Errorf("game %d: %w", num, err)
+	}
+	return g, nil
+}
+
+// reads a value for each of the spaces from the file
+func readHeat(path string, spaces int) ([]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.FieldsFunc(string(data), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	if len(fields) != spaces {
+		return nil, fmt.Errorf("%s has %d values, expected one for each of the %d spaces", path, len(fields), spaces)
+	}
+	heat := make([]float64, spaces)
+	for i, f := range fields {
+		if heat[i], err = strconv.ParseFloat(f, 64); err != nil {
+			return nil, fmt.Errorf("%s: invalid value %q", path, f)
+		}
+	}
+	return heat, nil
+}
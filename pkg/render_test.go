@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"image/gif"
+	"image/png"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"uttt/pkg/board"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	tests := []struct {
+		args []string
+		out  string
+		move int
+		rest []string
+	}{
+		{[]string{"--out", "a.svg", "games.uttt", "3"}, "a.svg", -1, []string{"games.uttt", "3"}},
+		{[]string{"games.uttt", "3", "--out", "a.svg"}, "a.svg", -1, []string{"games.uttt", "3"}},
+		{[]string{"games.uttt", "--move", "12", "3", "--out=a.png"}, "a.png", 12, []string{"games.uttt", "3"}},
+		// the - of a position where the next move is free isn't a flag
+		{[]string{"1X1O5/9/9/9/9/9/9/9/9", "-", "x", "--out", "a.svg"}, "a.svg", -1, []string{"1X1O5/9/9/9/9/9/9/9/9", "-", "x"}},
+		{[]string{"--out", "a.svg", "--", "--move", "2"}, "a.svg", -1, []string{"--move", "2"}},
+		{nil, "", -1, nil},
+	}
+	for _, test := range tests {
+		flags := flag.NewFlagSet("render", flag.ContinueOnError)
+		out := flags.String("out", "", "")
+		move := flags.Int("move", -1, "")
+		rest := parseInterspersed(flags, test.args)
+		if *out != test.out || *move != test.move || !reflect.DeepEqual(rest, test.rest) {
+			t.Errorf("parseInterspersed(%q) = --out %q --move %d %q, expected --out %q --move %d %q",
+				test.args, *out, *move, rest, test.out, test.move, test.rest)
+		}
+	}
+}
+
+// a game of the first legal move every turn
+func firstMovesGame(t *testing.T, n int) *board.Game {
+	g := board.NewGame(board.NewPosition(board.DefaultSize(), board.StandardRules{}))
+	for i := 0; i < n; i++ {
+		g.Play(g.Position().Moves()[0])
+	}
+	return g
+}
+
+func TestWriteImage(t *testing.T) {
+	g := firstMovesGame(t, 4)
+	history := g.History()
+	opts := board.RenderOptions{SpaceSize: 20, LastMove: history[len(history)-1]}
+	want := g.Position().Image(opts).Bounds()
+
+	var buf bytes.Buffer
+	if err := writeImage(&buf, g, opts, "position.svg", false, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	var root struct {
+		XMLName xml.Name
+		Width   int `xml:"width,attr"`
+		Height  int `xml:"height,attr"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatalf("wrote invalid SVG: %v", err)
+	}
+	if root.XMLName.Local != "svg" || root.Width != want.Dx() || root.Height != want.Dy() {
+		t.Errorf("wrote a %dx%d <%s>, expected a %dx%d <svg>", root.Width, root.Height, root.XMLName.Local, want.Dx(), want.Dy())
+	}
+	if !strings.Contains(buf.String(), board.RENDER_LAST_MOVE) {
+		t.Error("the SVG doesn't highlight the last move")
+	}
+	// anything but .png is an SVG, including stdout
+	var stdout bytes.Buffer
+	if err := writeImage(&stdout, g, opts, "", false, time.Second); err != nil || !bytes.Equal(stdout.Bytes(), buf.Bytes()) {
+		t.Errorf("wrote a different image to stdout than to an SVG file: %v", err)
+	}
+
+	buf.Reset()
+	if err := writeImage(&buf, g, opts, "position.png", false, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("wrote invalid PNG: %v", err)
+	}
+	if img.Bounds() != want {
+		t.Errorf("wrote a PNG of %v, expected %v", img.Bounds(), want)
+	}
+
+	buf.Reset()
+	if err := writeImage(&buf, g, opts, "game.gif", true, 500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("wrote invalid GIF: %v", err)
+	}
+	if anim.Config.Width != want.Dx() || anim.Config.Height != want.Dy() {
+		t.Errorf("wrote a %dx%d GIF, expected %dx%d", anim.Config.Width, anim.Config.Height, want.Dx(), want.Dy())
+	}
+	// a frame for the start and after every move, the last showing
+	// longer. Frames after the first only cover what changed
+	if len(anim.Image) != len(history)+1 {
+		t.Fatalf("wrote %d frames, expected %d", len(anim.Image), len(history)+1)
+	}
+	for i, frame := range anim.Image {
+		delay := 50
+		if i == len(anim.Image)-1 {
+			delay = 150
+		}
+		if !frame.Bounds().In(want) || (i == 0 && frame.Bounds() != want) || anim.Delay[i] != delay {
+			t.Errorf("frame %d is %v showing for %d, expected it in %v showing for %d", i, frame.Bounds(), anim.Delay[i], want, delay)
+		}
+	}
+}
+
+// failing writers fail every kind of image
+func TestWriteImageError(t *testing.T) {
+	g := firstMovesGame(t, 1)
+	for _, out := range []string{"a.svg", "a.png", "a.gif"} {
+		if err := writeImage(failingWriter{}, g, board.RenderOptions{}, out, strings.HasSuffix(out, ".gif"), time.Second); err == nil {
+			t.Errorf("wrote %s to a failing writer without an error", out)
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrShortWrite }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"uttt/pkg/board"
+	"uttt/pkg/game"
+	"uttt/pkg/record"
+)
+
+// runs `uttt replay [flags] <file> [game]`, stepping through a recorded
+// game in the terminal. Games are numbered from 1 in the order they're
+// in the file; without a number, the last game is replayed
+func replay(runner *game.Runner, args []string) {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Println("usage: uttt replay [flags] <file> [game]")
+		os.Exit(2)
+	}
+	records, err := record.ReadFile(args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(records) == 0 {
+		fmt.Printf("no games in %s\n", args[0])
+		os.Exit(1)
+	}
+
+	num := len(records)
+	if len(args) == 2 {
+		if num, err = strconv.Atoi(args[1]); err != nil || num < 1 || num > len(records) {
+			fmt.Printf("invalid game %q, expected 1 to %d\n", args[1], len(records))
+			os.Exit(2)
+		}
+	}
+	rec := records[num-1]
+	g, err := rec.Game()
+	if err != nil {
+		fmt.Printf("game %d: %v\n", num, err)
+		os.Exit(1)
+	}
+	for g.Takeback() {
+	}
+
+	fmt.Printf("game %d of %d: %s vs %s on %s, %s rules\n", num, len(records), rec.Player1, rec.Player2, rec.Date.Format(record.DATE_FORMAT), rec.Rules.Name())
+	for {
+		showReplay(rec, g)
+
+		fmt.Println("enter for the next move, b to go back, a move number to jump to it, pvp / pvai / aivp to play on from here, q to quit")
+		var inp string
+		if _, err := fmt.Scanln(&inp); err == io.EOF {
+			return
+		}
+
+		moves := len(g.History())
+		switch inp {
+		case "":
+			if moves < len(rec.Moves) {
+				g.Play(rec.Moves[moves])
+			}
+		case "b":
+			g.Takeback()
+		case "q":
+			return
+		case "pvp", "pvai", "aivp":
+			playOn(runner, rec, g.Position(), inp)
+		default:
+			target, err := strconv.Atoi(inp)
+			if err != nil || target < 0 || target > len(rec.Moves) {
+				fmt.Printf("expected a move number from 0 to %d\n", len(rec.Moves))
+				continue
+			}
+			for len(g.History()) > target {
+				g.Takeback()
+			}
+			for i := len(g.History()); i < target; i++ {
+				g.Play(rec.Moves[i])
+			}
+		}
+	}
+}
+
+// prints the replayed game's current position, with the last move highlighted
+func showReplay(rec *record.Record, g *board.Game) {
+	p := g.Position()
+	history := g.History()
+	if len(history) == 0 {
+		fmt.Printf("start, %d moves\n", len(rec.Moves))
+		fmt.Println(p.TerminalString())
+	} else {
+		last := history[len(history)-1]
+		fmt.Printf("move %d of %d: %v played %s\n", len(history), len(rec.Moves), p.Turn().Opponent(), rec.Size.MoveString(last))
+		fmt.Println(p.TerminalStringMove(last))
+	}
+	if comment, ok := rec.Comments[len(history)]; ok {
+		fmt.Printf("{%s}\n", comment)
+	}
+	if len(history) == len(rec.Moves) {
+		fmt.Println(game.Outcome{Result: rec.Result, Winner: rec.Winner})
+	}
+}
+
+// plays a new game from the position in the given mode
+func playOn(runner *game.Runner, rec *record.Record, p *board.Position, mode string) {
+	runner.Size, runner.Rules = rec.Size, rec.Rules
+	if err := runner.SetStart(p); err != nil {
+		fmt.Println(err)
+		return
+	}
+	switch mode {
+	case "pvp":
+		runner.RunPVP()
+	case "pvai":
+		runner.RunPVAI()
+	case "aivp":
+		runner.RunAIVP()
+	}
+}
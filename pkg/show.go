@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"uttt/pkg/board"
+)
+
+// runs `uttt show <position>`, printing the position along with
+// anything that keeps it from coming up in a game
+func show(args []string) {
+	if len(args) == 0 {
+		fmt.Println("usage: uttt show <position>")
+		os.Exit(2)
+	}
+	// the position's fields don't have to be quoted together
+	p, err := board.ParsePosition(strings.Join(args, " "))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Print(p.TerminalString())
+	fmt.Printf("%v to move, %s rules, %v\n", p.Turn(), p.Rules().Name(), p.Size())
+	for _, problem := range board.Validate(p) {
+		fmt.Println("impossible position:", problem)
+	}
+}
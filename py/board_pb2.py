@@ -13,7 +13,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x62oard.proto\x12\x04uttt\"!\n\x05\x43oord\x12\x0b\n\x03row\x18\x01 \x01(\x05\x12\x0b\n\x03\x63ol\x18\x02 \x01(\x05\">\n\x04Move\x12\x1a\n\x05large\x18\x01 \x01(\x0b\x32\x0b.uttt.Coord\x12\x1a\n\x05small\x18\x02 \x01(\x0b\x32\x0b.uttt.Coord\"!\n\x05Space\x12\x18\n\x03val\x18\x01 \x01(\x0e\x32\x0b.uttt.Owner\"#\n\x04\x43\x65ll\x12\x1b\n\x06spaces\x18\x01 \x03(\x0b\x32\x0b.uttt.Space\"\\\n\x05\x42oard\x12\x19\n\x05\x63\x65lls\x18\x01 \x03(\x0b\x32\n.uttt.Cell\x12\x1c\n\x07\x63urCell\x18\x02 \x01(\x0b\x32\x0b.uttt.Coord\x12\x0c\n\x04rows\x18\x03 \x01(\x05\x12\x0c\n\x04\x63ols\x18\x04 \x01(\x05\"\xb1\x01\n\x0cStateMessage\x12\x1a\n\x05\x62oard\x18\x01 \x01(\x0b\x32\x0b.uttt.Board\x12\x1f\n\ncellowners\x18\x02 \x03(\x0e\x32\x0b.uttt.Owner\x12\x19\n\x04turn\x18\x03 \x01(\x0e\x32\x0b.uttt.Owner\x12\x1b\n\x06winner\x18\x04 \x01(\x0e\x32\x0b.uttt.Owner\x12\x0c\n\x04\x64one\x18\x05 \x01(\x08\x12\x1e\n\nvalidmoves\x18\x06 \x03(\x0b\x32\n.uttt.Move\")\n\rActionMessage\x12\x18\n\x04move\x18\x01 \x01(\x0b\x32\n.uttt.Move\"A\n\rReturnMessage\x12!\n\x05state\x18\x01 \x01(\x0b\x32\x12.uttt.StateMessage\x12\r\n\x05valid\x18\x02 \x01(\x08*+\n\x05Owner\x12\x08\n\x04NONE\x10\x00\x12\x0b\n\x07PLAYER1\x10\x01\x12\x0b\n\x07PLAYER2\x10\x02\x42\x0bZ\tpkg/boardb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x62oard.proto\x12\x04uttt\"!\n\x05\x43oord\x12\x0b\n\x03row\x18\x01 \x01(\x05\x12\x0b\n\x03\x63ol\x18\x02 \x01(\x05\"X\n\x04Move\x12\x1a\n\x05large\x18\x01 \x01(\x0b\x32\x0b.uttt.Coord\x12\x1a\n\x05small\x18\x02 \x01(\x0b\x32\x0b.uttt.Coord\x12\x18\n\x03mid\x18\x03 \x03(\x0b\x32\x0b.uttt.Coord\"!\n\x05Space\x12\x18\n\x03val\x18\x01 \x01(\x0e\x32\x0b.uttt.Owner\"[\n\x04\x43\x65ll\x12\x1b\n\x06spaces\x18\x01 \x03(\x0b\x32\x0b.uttt.Space\x12\x1b\n\x06winner\x18\x02 \x01(\x0e\x32\x0b.uttt.Owner\x12\x19\n\x05\x63\x65lls\x18\x03 \x03(\x0b\x32\n.uttt.Cell\"\x9b\x01\n\x05\x42oard\x12\x19\n\x05\x63\x65lls\x18\x01 \x03(\x0b\x32\n.uttt.Cell\x12\x1c\n\x07\x63urCell\x18\x02 \x01(\x0b\x32\x0b.uttt.Coord\x12\x0c\n\x04rows\x18\x03 \x01(\x05\x12\x0c\n\x04\x63ols\x18\x04 \x01(\x05\x12\x0e\n\x06inarow\x18\x05 \x01(\x05\x12\x0e\n\x06levels\x18\x06 \x01(\x05\x12\x1d\n\x08\x63urCells\x18\x07 \x03(\x0b\x32\x0b.uttt.Coord\"\xde\x01\n\x0cStateMessage\x12\x1a\n\x05\x62oard\x18\x01 \x01(\x0b\x32\x0b.uttt.Board\x12\x1f\n\ncellowners\x18\x02 \x03(\x0e\x32\x0b.uttt.Owner\x12\x19\n\x04turn\x18\x03 \x01(\x0e\x32\x0b.uttt.Owner\x12\x1b\n\x06winner\x18\x04 \x01(\x0e\x32\x0b.uttt.Owner\x12\x0c\n\x04\x64one\x18\x05 \x01(\x08\x12\x1e\n\nvalidmoves\x18\x06 \x03(\x0b\x32\n.uttt.Move\x12\x1c\n\x06result\x18\x07 \x01(\x0e\x32\x0c.uttt.Result\x12\r\n\x05rules\x18\x08 \x01(\t\")\n\rActionMessage\x12\x18\n\x04move\x18\x01 \x01(\x0b\x32\n.uttt.Move\"#\n\x04Path\x12\x1b\n\x06\x63oords\x18\x01 \x03(\x0b\x32\x0b.uttt.Coord\"_\n\rThreatMessage\x12\x1b\n\x06player\x18\x01 \x01(\x0e\x32\x0b.uttt.Owner\x12\x18\n\x04\x63\x65ll\x18\x02 \x01(\x0b\x32\n.uttt.Path\x12\x17\n\x02\x61t\x18\x03 \x01(\x0b\x32\x0b.uttt.Coord\"\xf3\x01\n\x11MoveResultMessage\x12\x1b\n\x06player\x18\x01 \x01(\x0e\x32\x0b.uttt.Owner\x12\x1c\n\x08\x63\x61ptured\x18\x02 \x03(\x0b\x32\n.uttt.Path\x12\x19\n\x05\x64rawn\x18\x03 \x03(\x0b\x32\n.uttt.Path\x12\x1c\n\x06result\x18\x04 \x01(\x0e\x32\x0c.uttt.Result\x12\x1b\n\x06winner\x18\x05 \x01(\x0e\x32\x0b.uttt.Owner\x12\x0c\n\x04\x66ree\x18\x06 \x01(\x08\x12\x19\n\x04next\x18\x07 \x03(\x0b\x32\x0b.uttt.Coord\x12$\n\x07threats\x18\x08 \x03(\x0b\x32\x13.uttt.ThreatMessage\"\x88\x01\n\rReturnMessage\x12!\n\x05state\x18\x01 \x01(\x0b\x32\x12.uttt.StateMessage\x12\r\n\x05valid\x18\x02 \x01(\x08\x12\x1c\n\x06reason\x18\x03 \x01(\x0e\x32\x0c.uttt.Reason\x12\'\n\x06result\x18\x04 \x01(\x0b\x32\x17.uttt.MoveResultMessage\"+\n\nPlayerInfo\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\":\n\nEvaluation\x12\x0e\n\x06\x65ngine\x18\x01 \x01(\t\x12\r\n\x05score\x18\x02 \x01(\x02\x12\r\n\x05\x64\x65pth\x18\x03 \x01(\x05\"\x9b\x01\n\nMoveRecord\x12\x18\n\x04move\x18\x01 \x01(\x0b\x32\n.uttt.Move\x12\x1b\n\x06player\x18\x02 \x01(\x0e\x32\x0b.uttt.Owner\x12\x0c\n\x04time\x18\x03 \x01(\x03\x12\x11\n\tthinkTime\x18\x04 \x01(\x03\x12$\n\nevaluation\x18\x05 \x01(\x0b\x32\x10.uttt.Evaluation\x12\x0f\n\x07\x63omment\x18\x06 \x01(\t\"\xd3\x02\n\nGameRecord\x12!\n\x07player1\x18\x01 \x01(\x0b\x32\x10.uttt.PlayerInfo\x12!\n\x07player2\x18\x02 \x01(\x0b\x32\x10.uttt.PlayerInfo\x12\x0f\n\x07started\x18\x03 \x01(\x03\x12\r\n\x05rules\x18\x04 \x01(\t\x12\x0c\n\x04rows\x18\x05 \x01(\x05\x12\x0c\n\x04\x63ols\x18\x06 \x01(\x05\x12\x0e\n\x06inarow\x18\x07 \x01(\x05\x12\x0e\n\x06levels\x18\x08 \x01(\x05\x12\x0f\n\x07opening\x18\t \x01(\t\x12\x10\n\x08position\x18\n \x01(\t\x12\x13\n\x0btimeControl\x18\x0b \x01(\x03\x12\x1f\n\x05moves\x18\x0c \x03(\x0b\x32\x10.uttt.MoveRecord\x12\x1c\n\x06result\x18\r \x01(\x0e\x32\x0c.uttt.Result\x12\x1b\n\x06winner\x18\x0e \x01(\x0e\x32\x0b.uttt.Owner\x12\x0f\n\x07\x63omment\x18\x0f \x01(\t*+\n\x05Owner\x12\x08\n\x04NONE\x10\x00\x12\x0b\n\x07PLAYER1\x10\x01\x12\x0b\n\x07PLAYER2\x10\x02*{\n\x06Result\x12\x0b\n\x07ONGOING\x10\x00\x12\x0f\n\x0bPLAYER1_WIN\x10\x01\x12\x0f\n\x0bPLAYER2_WIN\x10\x02\x12\x08\n\x04\x44RAW\x10\x03\x12\x0f\n\x0bRESIGNATION\x10\x04\x12\x0b\n\x07TIMEOUT\x10\x05\x12\x0b\n\x07\x46ORFEIT\x10\x06\x12\r\n\tABANDONED\x10\x07*|\n\x06Reason\x12\x0c\n\x08\x41\x43\x43\x45PTED\x10\x00\x12\x0e\n\nWRONG_CELL\x10\x01\x12\x0f\n\x0bSPACE_TAKEN\x10\x02\x12\x0f\n\x0b\x43\x45LL_CLOSED\x10\x03\x12\x10\n\x0cOUT_OF_RANGE\x10\x04\x12\r\n\tGAME_OVER\x10\x05\x12\x11\n\rNOT_YOUR_TURN\x10\x06\x42\x0bZ\tpkg/boardb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -22,22 +22,40 @@ if _descriptor._USE_C_DESCRIPTORS == False:
 
   DESCRIPTOR._options = None
   DESCRIPTOR._serialized_options = b'Z\tpkg/board'
-  _globals['_OWNER']._serialized_start=576
-  _globals['_OWNER']._serialized_end=619
+  _globals['_OWNER']._serialized_start=1824
+  _globals['_OWNER']._serialized_end=1867
+  _globals['_RESULT']._serialized_start=1869
+  _globals['_RESULT']._serialized_end=1992
+  _globals['_REASON']._serialized_start=1994
+  _globals['_REASON']._serialized_end=2118
   _globals['_COORD']._serialized_start=21
   _globals['_COORD']._serialized_end=54
   _globals['_MOVE']._serialized_start=56
-  _globals['_MOVE']._serialized_end=118
-  _globals['_SPACE']._serialized_start=120
-  _globals['_SPACE']._serialized_end=153
-  _globals['_CELL']._serialized_start=155
-  _globals['_CELL']._serialized_end=190
-  _globals['_BOARD']._serialized_start=192
-  _globals['_BOARD']._serialized_end=284
-  _globals['_STATEMESSAGE']._serialized_start=287
-  _globals['_STATEMESSAGE']._serialized_end=464
-  _globals['_ACTIONMESSAGE']._serialized_start=466
-  _globals['_ACTIONMESSAGE']._serialized_end=507
-  _globals['_RETURNMESSAGE']._serialized_start=509
-  _globals['_RETURNMESSAGE']._serialized_end=574
+  _globals['_MOVE']._serialized_end=144
+  _globals['_SPACE']._serialized_start=146
+  _globals['_SPACE']._serialized_end=179
+  _globals['_CELL']._serialized_start=181
+  _globals['_CELL']._serialized_end=272
+  _globals['_BOARD']._serialized_start=275
+  _globals['_BOARD']._serialized_end=430
+  _globals['_STATEMESSAGE']._serialized_start=433
+  _globals['_STATEMESSAGE']._serialized_end=655
+  _globals['_ACTIONMESSAGE']._serialized_start=657
+  _globals['_ACTIONMESSAGE']._serialized_end=698
+  _globals['_PATH']._serialized_start=700
+  _globals['_PATH']._serialized_end=735
+  _globals['_THREATMESSAGE']._serialized_start=737
+  _globals['_THREATMESSAGE']._serialized_end=832
+  _globals['_MOVERESULTMESSAGE']._serialized_start=835
+  _globals['_MOVERESULTMESSAGE']._serialized_end=1078
+  _globals['_RETURNMESSAGE']._serialized_start=1081
+  _globals['_RETURNMESSAGE']._serialized_end=1217
+  _globals['_PLAYERINFO']._serialized_start=1219
+  _globals['_PLAYERINFO']._serialized_end=1262
+  _globals['_EVALUATION']._serialized_start=1264
+  _globals['_EVALUATION']._serialized_end=1322
+  _globals['_MOVERECORD']._serialized_start=1325
+  _globals['_MOVERECORD']._serialized_end=1480
+  _globals['_GAMERECORD']._serialized_start=1483
+  _globals['_GAMERECORD']._serialized_end=1822
 # @@protoc_insertion_point(module_scope)
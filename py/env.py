@@ -5,6 +5,7 @@ import os
 import time
 import socket
 import board_pb2 as pb
+from google.protobuf.internal.encoder import _VarintBytes
 
 # misc
 from typing import Tuple
@@ -21,6 +22,7 @@ MAX_TIMESTEPS = config["ENV"].getint("MAX_TIMESTEPS")
 ROWS = config["ENV"].getint("ROWS")
 COLS = config["ENV"].getint("COLS")
 CELLS = config["ENV"].getint("CELLS")
+LEVELS = config["ENV"].getint("LEVELS", fallback=2)
 
 # socket constants
 S_PORT = config["ENV"].getint("S_PORT")
@@ -34,6 +36,7 @@ CELL_REWARD = config["REWARD"].getfloat("CELL_REWARD")
 VALID_REWARD = config["REWARD"].getfloat("VALID_REWARD")
 INVALID_PENALTY = config["REWARD"].getfloat("INVALID_PENALTY")
 LOSS_PENALTY = config["REWARD"].getfloat("LOSS_PENALTY")
+TIE_REWARD = config["REWARD"].getfloat("TIE_REWARD")
 
 # misc
 SLEEP_TIME = config["ENV"].getfloat("SLEEP_TIME")
@@ -41,16 +44,32 @@ SLEEP_TIME = config["ENV"].getfloat("SLEEP_TIME")
 
 # env
 class UltimateTicTacToeEnv:
-    obs_dim = (9, 9, 4)
-    n_actions = CELLS * CELLS
+    obs_dim = (CELLS ** (LEVELS - 1), CELLS, 4)
+    n_actions = CELLS**LEVELS
 
     def __init__(self) -> None:
         self.s_conn, self.a_conn, self.r_conn = None, None, None
 
+    def _receive_exactly(self, conn: socket.socket, n: int) -> bytes:
+        b = bytearray()
+        while len(b) < n:
+            chunk = conn.recv(min(n - len(b), MAX_MSG_SIZE))
+            if not chunk:
+                raise ConnectionError("the game closed the connection")
+            b += chunk
+        return bytes(b)
+
     def _receive(self, conn: socket.socket, tp: type):
+        # every message is preceded by its size as a varint
+        size, shift = 0, 0
+        while True:
+            byte = self._receive_exactly(conn, 1)[0]
+            size |= (byte & 0x7F) << shift
+            shift += 7
+            if byte < 0x80:
+                break
         ret = tp()
-        b = conn.recv(MAX_MSG_SIZE)
-        ret.ParseFromString(b)
+        ret.ParseFromString(self._receive_exactly(conn, size))
         return ret
 
     def _get_return(self) -> pb.ReturnMessage:
@@ -64,35 +83,74 @@ class UltimateTicTacToeEnv:
 
     def _send_action(self, move) -> None:
         action = pb.ActionMessage(move=move)
-        self.a_conn.send(action.SerializeToString())
+        b = action.SerializeToString()
+        self.a_conn.sendall(_VarintBytes(len(b)) + b)
 
     def _to_idx(self, coord: pb.Coord) -> int:
         return coord.row * COLS + coord.col
 
+    def _to_path_idx(self, path) -> int:
+        idx = 0
+        for coord in path:
+            idx = idx * CELLS + self._to_idx(coord)
+        return idx
+
     def _to_multi_idx(self, move: pb.Move) -> int:
-        return self._to_idx(move.large) * CELLS + self._to_idx(move.small)
+        return self._to_path_idx([move.large, *move.mid, move.small])
+
+    def _walk_cells(self, cells, idx=0):
+        """
+        Yields the index and cell of every deepest cell (the ones holding
+        spaces) in row-major order level by level
+        """
+        for i, cell in enumerate(cells):
+            if len(cell.cells) > 0:
+                yield from self._walk_cells(cell.cells, idx * CELLS + i)
+            else:
+                yield idx * CELLS + i, cell
 
     def _process_state(self, state: pb.StateMessage) -> np.ndarray:
         """
         The structure of the state:
-        (9, 9, 4)
-        Outer 9 represent board cells
-        inner 9 represent the cell spaces
+        (CELLS ** (LEVELS - 1), CELLS, 4)
+        Outer CELLS ** (LEVELS - 1) represent the deepest cells, in
+            row-major order level by level (the board's cells on 2 levels)
+        inner CELLS represent the cell spaces
         each space has 3 objects:
             space owner (0, 1, 2) representing if the space is claimed or not
             cell owner (0, 1, 2) representing if the cell the space belongs to is claimed or not
-            curcellornot (0, 1); 1 if the space belongs to the current cell, 0 if not
+            curcellornot (0, 1); 1 if the space belongs to the current cell, 0 if not.
+                when the move is free (e.g. a free opening), every cell that
+                can be played in counts as the current cell
             turn (1, 2) 1 if the current turn is player1, 2 if the current turn is player2
         """
+        assert (
+            state.board.rows or 3,
+            state.board.cols or 3,
+            state.board.levels or 2,
+        ) == (
+            ROWS,
+            COLS,
+            LEVELS,
+        ), "train.ini ROWS, COLS and LEVELS don't match the board's size"
         board_state = np.zeros(self.obs_dim)
-        for cell_idx in range(len(state.board.cells)):
-            for space_idx in range(len(state.board.cells[cell_idx].spaces)):
-                board_state[cell_idx, space_idx, 0] = (
-                    state.board.cells[cell_idx].spaces[space_idx].val
-                )
-                board_state[cell_idx, space_idx, 1] = state.cellowners[cell_idx]
+        # the number of deepest cells in each outermost cell
+        per_cell = CELLS ** (LEVELS - 2)
+        if state.board.curCell.row >= 0 and state.board.curCell.col >= 0:
+            target = [state.board.curCell, *state.board.curCells][: LEVELS - 1]
+            width = CELLS ** (LEVELS - 1 - len(target))
+            start = self._to_path_idx(target) * width
+            cur_cells = set(range(start, start + width))
+        else:
+            cur_cells = {self._to_multi_idx(move) // CELLS for move in state.validmoves}
+        for cell_idx, cell in self._walk_cells(state.board.cells):
+            for space_idx in range(len(cell.spaces)):
+                board_state[cell_idx, space_idx, 0] = cell.spaces[space_idx].val
+                board_state[cell_idx, space_idx, 1] = state.cellowners[
+                    cell_idx // per_cell
+                ]
                 board_state[cell_idx, space_idx, 2] = (
-                    1 if self._to_idx(state.board.curCell) == cell_idx else 0
+                    1 if cell_idx in cur_cells else 0
                 )
                 board_state[cell_idx, space_idx, 3] = state.turn
 
@@ -105,8 +163,11 @@ class UltimateTicTacToeEnv:
 
     def _get_win_reward(self, msg: pb.ReturnMessage) -> float:
         """
-        Get's the reward for winning if the game was won
+        Get's the reward for winning if the game was won,
+        or for tying if the game was drawn
         """
+        if msg.state.result == pb.DRAW:
+            return TIE_REWARD
         # the turn sent in the return message should still be the caller's turn
         if msg.state.winner == msg.state.turn:
             if self.player_turn:
@@ -119,14 +180,9 @@ class UltimateTicTacToeEnv:
 
     def _get_cell_reward(self, msg: pb.ReturnMessage) -> float:
         """
-        Get's the reward for claiming a cell if a cell was claimed
+        Get's the reward for claiming a cell if the move won an outermost cell
         """
-        if self.prev_cellowners == msg.state.cellowners:
-            return 0
-        elif list(msg.state.cellowners).count(
-            msg.state.turn
-        ) > self.prev_cellowners.count(msg.state.turn):
-            self.prev_cellowners = list(msg.state.cellowners)
+        if any(len(cell.coords) == 1 for cell in msg.result.captured):
             return CELL_REWARD
         return 0
 
@@ -159,7 +215,6 @@ class UltimateTicTacToeEnv:
             )
 
     def _reset_vars(self):
-        self.prev_cellowners = [pb.NONE] * 9
         self.cur_state = None  # the current state; used for debugging
         self.won = False  # whether or not the player won
         self.done = False  # if the game is over
@@ -219,9 +274,9 @@ class UltimateTicTacToeEnv:
         self.cleanup()
 
     def to_move(self, idx: int) -> pb.Move:
-        outer_idx = idx // CELLS
-        inner_idx = idx % CELLS
+        path = []
+        for _ in range(LEVELS):
+            path.insert(0, self._make_coord(idx % CELLS))
+            idx //= CELLS
 
-        return pb.Move(
-            large=self._make_coord(outer_idx), small=self._make_coord(inner_idx)
-        )
+        return pb.Move(large=path[0], mid=path[1:-1], small=path[-1])
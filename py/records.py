@@ -0,0 +1,36 @@
+import board_pb2 as pb
+
+# varints as protobuf encodes them
+from google.protobuf.internal.decoder import _DecodeVarint32
+from google.protobuf.internal.encoder import _VarintBytes
+
+from typing import BinaryIO, Iterable, Iterator
+
+
+def read_game_records(f: BinaryIO) -> Iterator[pb.GameRecord]:
+    """
+    Yields every GameRecord in a length-delimited stream, as written by
+    record.StreamWriter in the Go program: each message is preceded by
+    its size as a varint
+    """
+    data = f.read()
+    pos = 0
+    while pos < len(data):
+        size, pos = _DecodeVarint32(data, pos)
+        if pos + size > len(data):
+            raise EOFError("the stream ends in the middle of a game record")
+        record = pb.GameRecord()
+        record.ParseFromString(data[pos : pos + size])
+        pos += size
+        yield record
+
+
+def write_game_records(f: BinaryIO, records: Iterable[pb.GameRecord]) -> None:
+    """
+    Writes the records to a length-delimited stream that
+    record.StreamReader in the Go program can read
+    """
+    for record in records:
+        data = record.SerializeToString()
+        f.write(_VarintBytes(len(data)))
+        f.write(data)